@@ -0,0 +1,165 @@
+package collection
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Auth describes how a request authenticates. It may be set on the
+// collection, on a folder or on a request. Postman stores the settings of
+// every auth type the user has configured, keyed by the type name, so
+// switching Type does not lose the others.
+type Auth struct {
+	Type   string
+	Params map[string][]AuthAttribute
+
+	extra map[string]json.RawMessage
+}
+
+// Attributes returns the settings of the active auth type
+func (a *Auth) Attributes() []AuthAttribute {
+	return a.Params[a.Type]
+}
+
+// Get returns the value of the attribute key of the active auth type
+func (a *Auth) Get(key string) string {
+	for _, attr := range a.Params[a.Type] {
+		if attr.Key == key {
+			return attr.String()
+		}
+	}
+	return ""
+}
+
+// Set sets the value of the attribute key of the active auth type
+func (a *Auth) Set(key, value string) {
+	if a.Params == nil {
+		a.Params = make(map[string][]AuthAttribute)
+	}
+	attrs := a.Params[a.Type]
+	for i := range attrs {
+		if attrs[i].Key == key {
+			attrs[i].Value = value
+			return
+		}
+	}
+	a.Params[a.Type] = append(attrs, AuthAttribute{Key: key, Value: value, Type: "string"})
+}
+
+// Clone returns a copy of the auth whose settings can be changed without
+// affecting a
+func (a *Auth) Clone() *Auth {
+	clone := *a
+	if a.Params != nil {
+		clone.Params = make(map[string][]AuthAttribute, len(a.Params))
+		for k, attrs := range a.Params {
+			clone.Params[k] = append([]AuthAttribute(nil), attrs...)
+		}
+	}
+	return &clone
+}
+
+// EffectiveAuth returns the auth that applies to a request given the auth of
+// the request followed by the auth of its folders, closest first, and of the
+// collection. A nil auth inherits from its parent. The result is nil when no
+// level sets auth.
+func EffectiveAuth(levels ...*Auth) *Auth {
+	for _, auth := range levels {
+		if auth != nil {
+			return auth
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (a *Auth) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	*a = Auth{}
+	for k, v := range members {
+		if k == "type" {
+			if err := json.Unmarshal(v, &a.Type); err != nil {
+				return err
+			}
+			continue
+		}
+		var attrs []AuthAttribute
+		if err := json.Unmarshal(v, &attrs); err != nil {
+			// not a v2.1 attribute list, keep it as it is
+			if a.extra == nil {
+				a.extra = make(map[string]json.RawMessage)
+			}
+			a.extra[k] = v
+			continue
+		}
+		if a.Params == nil {
+			a.Params = make(map[string][]AuthAttribute)
+		}
+		a.Params[k] = attrs
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (a Auth) MarshalJSON() ([]byte, error) {
+	extra := make(map[string]json.RawMessage, len(a.Params)+len(a.extra))
+	for k, v := range a.extra {
+		extra[k] = v
+	}
+	for k, params := range a.Params {
+		attrs, err := marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		extra[k] = attrs
+	}
+	return encodeObject(&struct {
+		Type string `json:"type"`
+	}{a.Type}, extra)
+}
+
+// AuthAttribute is a single setting of an auth type, e.g. the username of
+// basic auth. Values are usually strings but may be any JSON value.
+type AuthAttribute struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+	Type  string `json:"type,omitempty"`
+
+	extra map[string]json.RawMessage
+}
+
+// String returns the value as text
+func (a AuthAttribute) String() string {
+	switch v := a.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64, bool:
+		return fmt.Sprint(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (a *AuthAttribute) UnmarshalJSON(data []byte) error {
+	type authAttribute AuthAttribute
+	extra, err := decodeObject(data, (*authAttribute)(a))
+	a.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler
+func (a AuthAttribute) MarshalJSON() ([]byte, error) {
+	type authAttribute AuthAttribute
+	return encodeObject((*authAttribute)(&a), a.extra)
+}
@@ -1,67 +1,132 @@
 package collection
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 )
 
-// Cllns represents the structure of the collection JSON (Postman collection format)
-type Cllns struct {
-	Info struct {
-		Name string `json:"name"`
-	} `json:"info"`
-	Item []struct {
-		Name    string `json:"name"`
-		Request struct {
-			Method      string `json:"method"`
-			Description string `json:"description"`
-			Header      []struct {
-				Key   string `json:"key"`
-				Value string `json:"value"`
-			} `json:"header"`
-			Body struct {
-				Mode string `json:"mode"`
-				Raw  string `json:"raw"`
-			} `json:"body"`
-			URL struct {
-				Raw  string   `json:"raw"`
-				Host []string `json:"host"`
-				Path []string `json:"path"`
-			} `json:"url"`
-		} `json:"request"`
-	} `json:"item"`
-	Variable []struct {
-		Key   string `json:"key"`
-		Value string `json:"value"`
-		Type  string `json:"type"`
-	} `json:"variable"`
+// Collection represents a Postman collection (schema v2.1).
+// Members the model does not know are kept and written back unchanged.
+type Collection struct {
+	Info                    Info            `json:"info"`
+	Item                    []Item          `json:"item"`
+	Event                   []Event         `json:"event,omitempty"`
+	Variable                []Variable      `json:"variable,omitempty"`
+	Auth                    *Auth           `json:"auth,omitempty"`
+	ProtocolProfileBehavior json.RawMessage `json:"protocolProfileBehavior,omitempty"`
+
+	extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (c *Collection) UnmarshalJSON(data []byte) error {
+	type collection Collection
+	extra, err := decodeObject(data, (*collection)(c))
+	c.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler
+func (c Collection) MarshalJSON() ([]byte, error) {
+	type collection Collection
+	return encodeObject((*collection)(&c), c.extra)
+}
+
+// Info holds the collection metadata
+type Info struct {
+	PostmanID   string          `json:"_postman_id,omitempty"`
+	Name        string          `json:"name"`
+	Description Description     `json:"description,omitzero"`
+	Schema      string          `json:"schema,omitempty"`
+	Version     json.RawMessage `json:"version,omitempty"`
+
+	extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (i *Info) UnmarshalJSON(data []byte) error {
+	type info Info
+	extra, err := decodeObject(data, (*info)(i))
+	i.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler
+func (i Info) MarshalJSON() ([]byte, error) {
+	type info Info
+	return encodeObject((*info)(&i), i.extra)
 }
 
-// substituteVariables replaces {{var}} in a string with values from vars
-func SubstituteVariables(s string, vars map[string]string) string {
-	for k, v := range vars {
-		placeholder := "{{" + k + "}}"
-		s = strings.ReplaceAll(s, placeholder, v)
+// Item represents a request or a folder of nested items in the collection
+type Item struct {
+	ID                      string          `json:"id,omitempty"`
+	PostmanID               string          `json:"_postman_id,omitempty"`
+	Name                    string          `json:"name"`
+	Description             Description     `json:"description,omitzero"`
+	Variable                []Variable      `json:"variable,omitempty"`
+	Event                   []Event         `json:"event,omitempty"`
+	Auth                    *Auth           `json:"auth,omitempty"`
+	Item                    []Item          `json:"item,omitempty"`
+	Request                 *Request        `json:"request,omitempty"`
+	Response                []Response      `json:"response,omitempty"`
+	ProtocolProfileBehavior json.RawMessage `json:"protocolProfileBehavior,omitempty"`
+
+	extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (i *Item) UnmarshalJSON(data []byte) error {
+	type item Item
+	extra, err := decodeObject(data, (*item)(i))
+	i.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler
+func (i Item) MarshalJSON() ([]byte, error) {
+	type item Item
+	extra := i.extra
+	if i.Item != nil && len(i.Item) == 0 {
+		// keep empty folders as folders
+		extra = make(map[string]json.RawMessage, len(i.extra)+1)
+		for k, v := range i.extra {
+			extra[k] = v
+		}
+		extra["item"] = json.RawMessage("[]")
 	}
-	return s
+	return encodeObject((*item)(&i), extra)
 }
 
-// LoadPostmanCollection loads and parses the Postman collection from file
-type FormMeta struct {
-	ID    string
-	Title string
-	Intro string
+// IsFolder reports whether the item is a folder rather than a request
+func (i Item) IsFolder() bool {
+	return i.Item != nil
 }
 
-func LoadPostmanCollection(path string) (*Cllns, error) {
+// StableID returns the Postman id of the item when present, otherwise a
+// deterministic hash of its parent, position and name, so the same item gets
+// the same ID across reloads.
+func (i Item) StableID(parentID string, idx int) string {
+	if i.ID != "" {
+		return i.ID
+	}
+	if i.PostmanID != "" {
+		return i.PostmanID
+	}
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s/%d/%s", parentID, idx, i.Name)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// LoadPostmanCollection loads and parses the Postman collection from file
+func LoadPostmanCollection(path string) (*Collection, error) {
 	data, err := os.ReadFile(filepath.Join(path))
 	if err != nil {
 		return nil, fmt.Errorf("error reading Postman collection: %v", err)
 	}
-	var collection Cllns
+	var collection Collection
 	if err := json.Unmarshal(data, &collection); err != nil {
 		return nil, fmt.Errorf("error parsing Postman collection: %v", err)
 	}
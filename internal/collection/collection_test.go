@@ -1,7 +1,12 @@
 package collection
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -15,6 +20,91 @@ func TestSubstituteVariables(t *testing.T) {
 	}
 }
 
+func TestResolveVariables(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		vars      map[string]string
+		want      string
+		undefined []string
+		cycle     string
+	}{
+		{
+			name:  "nested",
+			input: "{{api_url}}/users",
+			vars:  map[string]string{"api_url": "{{base_url}}/v2", "base_url": "https://{{host}}", "host": "example.com"},
+			want:  "https://example.com/v2/users",
+		},
+		{
+			name:  "composed name",
+			input: "{{url_{{env}}}}",
+			vars:  map[string]string{"env": "prod", "url_prod": "https://prod"},
+			want:  "https://prod",
+		},
+		{
+			name:      "undefined in value",
+			input:     "{{a}} {{b}} {{a}} {{$guid}}",
+			vars:      map[string]string{"a": "{{missing}}/x"},
+			want:      "{{missing}}/x {{b}} {{missing}}/x {{$guid}}",
+			undefined: []string{"missing", "b"},
+		},
+		{
+			name:  "cycle",
+			input: "{{a}} {{c}}",
+			vars:  map[string]string{"a": "{{b}}", "b": "x{{a}}", "c": "ok"},
+			want:  "{{a}} ok",
+			cycle: "a -> b -> a",
+		},
+		{
+			name:  "self reference",
+			input: "{{a}}",
+			vars:  map[string]string{"a": "{{a}}!"},
+			want:  "{{a}}",
+			cycle: "a -> a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, undefined, err := ResolveVariables(tt.input, tt.vars)
+			if got != tt.want {
+				t.Errorf("ResolveVariables() = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(undefined, tt.undefined) {
+				t.Errorf("ResolveVariables() undefined = %v, want %v", undefined, tt.undefined)
+			}
+			switch {
+			case tt.cycle == "" && err != nil:
+				t.Errorf("ResolveVariables() error = %v", err)
+			case tt.cycle != "" && (!errors.Is(err, ErrVariableCycle) || !strings.HasSuffix(err.Error(), tt.cycle)):
+				t.Errorf("ResolveVariables() error = %v, want cycle %s", err, tt.cycle)
+			}
+		})
+	}
+}
+
+func TestSubstituteVariablesNestedIsDeterministic(t *testing.T) {
+	vars := map[string]string{"api_url": "{{base_url}}/v2", "base_url": "http://localhost"}
+	for range 20 {
+		if got := SubstituteVariables("{{api_url}}", vars); got != "http://localhost/v2" {
+			t.Fatalf("SubstituteVariables() = %q", got)
+		}
+	}
+}
+
+func TestMergeVariables(t *testing.T) {
+	collectionVars := map[string]string{"base": "http://localhost", "id": "1"}
+	environmentVars := map[string]string{"base": "https://staging.example.com", "token": "t"}
+
+	got := MergeVariables(collectionVars, environmentVars)
+	want := map[string]string{"base": "https://staging.example.com", "id": "1", "token": "t"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeVariables() = %v, want %v", got, want)
+	}
+	if collectionVars["base"] != "http://localhost" {
+		t.Errorf("MergeVariables() modified its input: %v", collectionVars)
+	}
+}
+
 func TestLoadPostmanCollection(t *testing.T) {
 	// Создаём временный файл с минимальной коллекцией
 	jsonData := `{"info":{"name":"Test"},"item":[],"variable":[{"key":"foo","value":"bar","type":"string"}]}`
@@ -39,3 +129,516 @@ func TestLoadPostmanCollection(t *testing.T) {
 		t.Errorf("unexpected variable: %+v", coll.Variable)
 	}
 }
+
+func TestLoadPostmanCollectionNestedFolders(t *testing.T) {
+	jsonData := `{"info":{"name":"Test"},"item":[
+		{"name":"Users","description":"User endpoints","item":[
+			{"name":"Admin","item":[
+				{"name":"List admins","request":{"method":"GET","url":{"raw":"http://x/admins"}}}
+			]},
+			{"name":"List users","request":{"method":"GET","url":{"raw":"http://x/users"}}}
+		]},
+		{"name":"Empty","item":[]},
+		{"name":"Health","request":{"method":"GET","url":{"raw":"http://x/health"}}}
+	]}`
+	f, err := os.CreateTemp("", "col_test_*.json")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(jsonData); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	f.Close()
+
+	coll, err := LoadPostmanCollection(f.Name())
+	if err != nil {
+		t.Fatalf("LoadPostmanCollection error: %v", err)
+	}
+	if len(coll.Item) != 3 {
+		t.Fatalf("unexpected top-level items: %d", len(coll.Item))
+	}
+	users := coll.Item[0]
+	if !users.IsFolder() || users.Description.String() != "User endpoints" || len(users.Item) != 2 {
+		t.Errorf("unexpected folder: %+v", users)
+	}
+	if admin := users.Item[0]; !admin.IsFolder() || admin.Item[0].Name != "List admins" || admin.Item[0].IsFolder() {
+		t.Errorf("unexpected nested folder: %+v", admin)
+	}
+	if users.Item[1].Name != "List users" || users.Item[1].Request.Method != "GET" {
+		t.Errorf("unexpected order or request: %+v", users.Item[1])
+	}
+	if !coll.Item[1].IsFolder() {
+		t.Errorf("empty folder should be a folder")
+	}
+	if coll.Item[2].IsFolder() {
+		t.Errorf("request should not be a folder")
+	}
+}
+
+func TestCollection_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    Collection
+		wantErr bool
+	}{
+		{
+			name: "valid collection",
+			json: `{
+				"info": {
+					"name": "Test Collection"
+				},
+				"item": [
+					{
+						"name": "Test Request",
+						"request": {
+							"method": "GET",
+							"description": "Test Description",
+							"header": [
+								{
+									"key": "Content-Type",
+									"value": "application/json"
+								}
+							],
+							"body": {
+								"mode": "raw",
+								"raw": "{\"test\": \"value\"}"
+							},
+							"url": {
+								"raw": "http://example.com",
+								"host": ["example.com"],
+								"path": ["test"]
+							}
+						}
+					}
+				],
+				"variable": [
+					{
+						"key": "baseUrl",
+						"value": "http://example.com",
+						"type": "string"
+					}
+				]
+			}`,
+			want: Collection{
+				Info: Info{
+					Name: "Test Collection",
+				},
+				Item: []Item{
+					{
+						Name: "Test Request",
+						Request: &Request{
+							Method:      "GET",
+							Description: Description{Content: "Test Description"},
+							Header: HeaderList{
+								{
+									Key:   "Content-Type",
+									Value: "application/json",
+								},
+							},
+							Body: &Body{
+								Mode: "raw",
+								Raw:  "{\"test\": \"value\"}",
+							},
+							URL: URL{
+								Raw:  "http://example.com",
+								Host: StringList{"example.com"},
+								Path: StringList{"test"},
+							},
+						},
+					},
+				},
+				Variable: []Variable{
+					{
+						Key:   "baseUrl",
+						Value: "http://example.com",
+						Type:  "string",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "invalid json",
+			json:    `{invalid json}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Collection
+			err := json.Unmarshal([]byte(tt.json), &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Collection.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				if got.Info.Name != tt.want.Info.Name {
+					t.Errorf("Collection.Info.Name = %v, want %v", got.Info.Name, tt.want.Info.Name)
+				}
+				if len(got.Item) != len(tt.want.Item) {
+					t.Errorf("Collection.Item length = %v, want %v", len(got.Item), len(tt.want.Item))
+				}
+				if len(got.Variable) != len(tt.want.Variable) {
+					t.Errorf("Collection.Variable length = %v, want %v", len(got.Variable), len(tt.want.Variable))
+				}
+				if !reflect.DeepEqual(got.Item, tt.want.Item) {
+					t.Errorf("Collection.Item = %+v, want %+v", got.Item, tt.want.Item)
+				}
+			}
+		})
+	}
+}
+
+func TestCollection_RoundTrip(t *testing.T) {
+	input := `{
+		"info": {"_postman_id": "c1", "name": "Full", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json", "x-custom": {"a": 1}},
+		"item": [
+			{
+				"id": "f1",
+				"name": "Folder",
+				"description": {"content": "# Docs", "type": "text/markdown"},
+				"auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}", "type": "string"}]},
+				"item": [
+					{
+						"id": "r1",
+						"name": "Create",
+						"event": [{"listen": "test", "script": {"type": "text/javascript", "exec": ["pm.test('ok', () => {});"]}}],
+						"protocolProfileBehavior": {"disableBodyPruning": true},
+						"request": {
+							"method": "POST",
+							"header": [{"key": "Accept", "value": "*/*", "type": "text", "disabled": true}],
+							"body": {"mode": "urlencoded", "urlencoded": [{"key": "a", "value": "<b>&", "type": "text"}]},
+							"url": {"raw": "{{base}}/items?x=1", "host": ["{{base}}"], "path": ["items"], "query": [{"key": "x", "value": "1"}]},
+							"proxy": {"host": "proxy.local"}
+						},
+						"response": [{"name": "Created", "code": 201, "status": "Created", "body": "{}", "_postman_previewlanguage": "json"}]
+					},
+					{"name": "Empty folder", "item": []}
+				]
+			},
+			{"name": "Bare", "request": "https://example.com/health"}
+		],
+		"event": [{"listen": "prerequest", "script": {"exec": "console.log(1)"}}],
+		"variable": [{"key": "port", "value": 8080, "type": "number"}, {"key": "base", "value": "http://localhost"}],
+		"auth": {"type": "basic", "basic": [{"key": "username", "value": "u"}], "bearer": [{"key": "token", "value": "t"}]},
+		"protocolProfileBehavior": {"followRedirects": false}
+	}`
+
+	var coll Collection
+	if err := json.Unmarshal([]byte(input), &coll); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	output, err := json.Marshal(coll)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	var want, got any
+	if err := json.Unmarshal([]byte(input), &want); err != nil {
+		t.Fatalf("Unmarshal input error: %v", err)
+	}
+	if err := json.Unmarshal(output, &got); err != nil {
+		t.Fatalf("Unmarshal output error: %v", err)
+	}
+	// a bare request string is written back as an object
+	want.(map[string]any)["item"].([]any)[1].(map[string]any)["request"] = map[string]any{
+		"url": map[string]any{"raw": "https://example.com/health"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch:\n got: %s\nwant: %s", output, input)
+	}
+
+	folder := coll.Item[0]
+	if folder.Description.String() != "# Docs" || folder.Auth.Get("token") != "{{token}}" {
+		t.Errorf("unexpected folder: %+v", folder)
+	}
+	if !folder.Item[1].IsFolder() {
+		t.Errorf("empty folder should be a folder")
+	}
+	if coll.Item[1].Request.URL.Raw != "https://example.com/health" {
+		t.Errorf("unexpected bare request: %+v", coll.Item[1].Request)
+	}
+	if coll.Variable[0].Value != "8080" || coll.Auth.Get("username") != "u" {
+		t.Errorf("unexpected collection variables or auth: %+v %+v", coll.Variable, coll.Auth)
+	}
+}
+
+func TestURL_SetRawParts(t *testing.T) {
+	tests := []struct {
+		raw   string
+		host  StringList
+		port  string
+		query []string
+	}{
+		{"http://x/a?", StringList{"x"}, "", nil},
+		{"http://x/a?&b=1&&c", StringList{"x"}, "", []string{"b", "c"}},
+		{"http://x:8080/a?b", StringList{"x"}, "8080", []string{"b"}},
+		{"{{base}}/a", StringList{"{{base}}"}, "", nil},
+		{"{{host}}:{{port}}/a", StringList{"{{host}}"}, "{{port}}", nil},
+		{"{{host}}:8080/a", StringList{"{{host}}"}, "8080", nil},
+		{"api.{{domain}}:443", StringList{"api", "{{domain}}"}, "443", nil},
+	}
+	for _, tt := range tests {
+		var u URL
+		u.SetRaw(tt.raw)
+		var keys []string
+		for _, q := range u.Query {
+			keys = append(keys, q.Key)
+		}
+		if !reflect.DeepEqual(u.Host, tt.host) || u.Port != tt.port || !reflect.DeepEqual(keys, tt.query) {
+			t.Errorf("SetRaw(%q) = host %q, port %q, query %q, want %q, %q, %q", tt.raw, u.Host, u.Port, keys, tt.host, tt.port, tt.query)
+		}
+	}
+}
+
+func TestURL_PathObjectsRoundTrip(t *testing.T) {
+	input := `{"raw":"{{base}}/v1/users","host":["{{base}}"],"path":["v1",{"type":"string","value":"users","description":"the users"}]}`
+	var u URL
+	if err := json.Unmarshal([]byte(input), &u); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(u.Path, StringList{"v1", "users"}) {
+		t.Errorf("Path = %v, want [v1 users]", u.Path)
+	}
+	if output, err := json.Marshal(u); err != nil || string(output) != input {
+		t.Errorf("Marshal = %s, %v, want %s", output, err, input)
+	}
+
+	// an unchanged segment keeps its object, a changed one is a string
+	u.SetRaw("{{base}}/v2/users")
+	if output, _ := json.Marshal(u); !strings.Contains(string(output), `"path":["v2",{"type":"string","value":"users","description":"the users"}]`) {
+		t.Errorf("object of an unchanged segment not kept: %s", output)
+	}
+	u.SetRaw("{{base}}/v1/items")
+	if output, _ := json.Marshal(u); !strings.Contains(string(output), `"path":["v1","items"]`) {
+		t.Errorf("object of a changed segment kept: %s", output)
+	}
+}
+
+func TestItem_StableID(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     Item
+		parentID string
+		idx      int
+		want     string
+	}{
+		{
+			name: "postman id",
+			item: Item{ID: "req-1", PostmanID: "legacy-1", Name: "Get users"},
+			want: "req-1",
+		},
+		{
+			name: "legacy postman id",
+			item: Item{PostmanID: "legacy-1", Name: "Get users"},
+			want: "legacy-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.item.StableID(tt.parentID, tt.idx); got != tt.want {
+				t.Errorf("Item.StableID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("hash is stable and position dependent", func(t *testing.T) {
+		item := Item{Name: "Get users"}
+		first := item.StableID("", 0)
+		if first == "" || first != item.StableID("", 0) {
+			t.Errorf("Item.StableID() is not stable: %q", first)
+		}
+		if first == item.StableID("", 1) {
+			t.Errorf("Item.StableID() should differ by position")
+		}
+		if first == item.StableID("folder", 0) {
+			t.Errorf("Item.StableID() should differ by parent")
+		}
+	})
+}
+
+func TestUnresolvedVariables(t *testing.T) {
+	vars := map[string]string{"base": "http://localhost", "empty": ""}
+	input := "{{base}}/users/{{id}}?q={{empty}}&r={{id}}&s={{missing}}"
+
+	got := UnresolvedVariables(input, vars)
+	if want := []string{"id", "missing"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UnresolvedVariables() = %v, want %v", got, want)
+	}
+
+	placeholders := Placeholders(input)
+	if len(placeholders) != 5 || input[placeholders[1][0]:placeholders[1][1]] != "{{id}}" {
+		t.Errorf("unexpected placeholders: %v", placeholders)
+	}
+}
+
+func TestURL_SetRaw(t *testing.T) {
+	u := URL{Query: []QueryParam{
+		{Key: "page", Value: "1", Description: Description{Content: "page number"}},
+		{Key: "debug", Value: "true", Disabled: true},
+	}}
+	u.SetRaw("https://api.example.com:8443/v1/users?page=2&sort#top")
+
+	if u.Protocol != "https" || u.Port != "8443" || u.Hash != "top" {
+		t.Errorf("unexpected protocol, port or hash: %+v", u)
+	}
+	if !reflect.DeepEqual(u.Host, StringList{"api", "example", "com"}) || !reflect.DeepEqual(u.Path, StringList{"v1", "users"}) {
+		t.Errorf("unexpected host or path: %v %v", u.Host, u.Path)
+	}
+	if len(u.Query) != 3 || u.Query[0].Value != "2" || u.Query[0].Description.String() != "page number" ||
+		u.Query[1].Key != "sort" || !u.Query[2].Disabled {
+		t.Errorf("unexpected query: %+v", u.Query)
+	}
+
+	u.SetRaw("{{base}}/users")
+	if !reflect.DeepEqual(u.Host, StringList{"{{base}}"}) || u.Port != "" || !reflect.DeepEqual(u.Path, StringList{"users"}) {
+		t.Errorf("unexpected templated url: %+v", u)
+	}
+}
+
+func TestEffectiveAuth(t *testing.T) {
+	collectionAuth := &Auth{Type: "bearer"}
+	folderAuth := &Auth{Type: "basic"}
+	requestAuth := &Auth{Type: "noauth"}
+
+	tests := []struct {
+		name   string
+		levels []*Auth
+		want   *Auth
+	}{
+		{"request", []*Auth{requestAuth, folderAuth, collectionAuth}, requestAuth},
+		{"folder", []*Auth{nil, folderAuth, collectionAuth}, folderAuth},
+		{"collection", []*Auth{nil, nil, collectionAuth}, collectionAuth},
+		{"none", []*Auth{nil, nil, nil}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EffectiveAuth(tt.levels...); got != tt.want {
+				t.Errorf("EffectiveAuth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuth_Clone(t *testing.T) {
+	var auth Auth
+	if err := json.Unmarshal([]byte(`{"type":"basic","basic":[{"key":"username","value":"u"}],"bearer":[{"key":"token","value":"t"}]}`), &auth); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	clone := auth.Clone()
+	clone.Set("username", "other")
+	clone.Type = "bearer"
+	clone.Set("token", "changed")
+
+	if auth.Type != "basic" || auth.Get("username") != "u" || auth.Params["bearer"][0].String() != "t" {
+		t.Errorf("Clone() shares settings with the original: %+v", auth)
+	}
+	if clone.Get("token") != "changed" || clone.Params["basic"][0].String() != "other" {
+		t.Errorf("unexpected clone: %+v", clone)
+	}
+}
+
+func TestBody_RawLanguage(t *testing.T) {
+	var b Body
+	if err := json.Unmarshal([]byte(`{"mode":"raw","raw":"<a/>","options":{"raw":{"language":"xml","headerFamily":"x"},"other":1}}`), &b); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if got := b.RawLanguage(); got != "xml" {
+		t.Errorf("RawLanguage() = %q, want xml", got)
+	}
+
+	if err := b.SetRawLanguage("json"); err != nil {
+		t.Fatalf("SetRawLanguage error: %v", err)
+	}
+	if want := `{"other":1,"raw":{"headerFamily":"x","language":"json"}}`; string(b.Options) != want {
+		t.Errorf("Options = %s, want %s", b.Options, want)
+	}
+
+	var empty Body
+	if empty.RawLanguage() != "" {
+		t.Errorf("RawLanguage() of a body without options = %q", empty.RawLanguage())
+	}
+	if err := empty.SetRawLanguage("text"); err != nil || string(empty.Options) != `{"raw":{"language":"text"}}` {
+		t.Errorf("SetRawLanguage() = %s, %v", empty.Options, err)
+	}
+}
+
+func TestFormParam_Files(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string
+	}{
+		{`"/tmp/a.txt"`, []string{"/tmp/a.txt"}},
+		{`["/tmp/a.txt","/tmp/b.txt"]`, []string{"/tmp/a.txt", "/tmp/b.txt"}},
+		{`""`, nil},
+		{``, nil},
+	}
+	for _, tt := range tests {
+		f := FormParam{Key: "f", Type: "file", Src: json.RawMessage(tt.src)}
+		if got := f.Files(); !slices.Equal(got, tt.want) {
+			t.Errorf("Files() of %s = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+
+	var f FormParam
+	for _, paths := range [][]string{{"/a"}, {"/a", "/b"}, nil} {
+		if err := f.SetFiles(paths); err != nil {
+			t.Fatalf("SetFiles error: %v", err)
+		}
+		if got := f.Files(); !slices.Equal(got, paths) {
+			t.Errorf("Files() after SetFiles(%q) = %q", paths, got)
+		}
+	}
+	if f.Src != nil {
+		t.Errorf("Src = %s after removing the files, want nil", f.Src)
+	}
+}
+
+func TestURL_SetRawPathVariables(t *testing.T) {
+	u := URL{Variable: []Variable{{Key: "id", Value: "42", Description: Description{Content: "user id"}}, {Key: "gone", Value: "x"}}}
+	u.SetRaw("{{base}}/users/:id/posts/:postId/:id")
+
+	if len(u.Variable) != 2 {
+		t.Fatalf("Variable = %+v, want id and postId", u.Variable)
+	}
+	if v := u.Variable[0]; v.Key != "id" || v.Value != "42" || v.Description.String() != "user id" {
+		t.Errorf("Variable[0] = %+v, want id kept", v)
+	}
+	if v := u.Variable[1]; v.Key != "postId" || v.Value != "" {
+		t.Errorf("Variable[1] = %+v, want a new postId", v)
+	}
+}
+
+func TestURL_SetQuery(t *testing.T) {
+	u := URL{}
+	u.SetRaw("https://example.com/search?old=1#results")
+	u.SetQuery([]QueryParam{
+		{Key: "q", Value: "a&b #1", Description: Description{Content: "search"}},
+		{Key: "debug", Value: "true", Disabled: true},
+		{Key: "k=v", Value: ""},
+		{Key: "page", Value: "{{page}}"},
+	})
+
+	if want := "https://example.com/search?q=a%26b %231&k%3Dv&page={{page}}#results"; u.Raw != want {
+		t.Errorf("Raw = %q, want %q", u.Raw, want)
+	}
+	if len(u.Query) != 4 || u.Query[0].Value != "a%26b %231" || u.Query[0].Description.String() != "search" || !u.Query[1].Disabled {
+		t.Errorf("unexpected query: %+v", u.Query)
+	}
+
+	// the raw URL parses back into the same parameters
+	u.SetRaw(u.Raw)
+	if len(u.Query) != 4 || u.Query[0].Key != "q" || u.Query[1].Key != "k%3Dv" || u.Query[2].Value != "{{page}}" || u.Query[3].Key != "debug" {
+		t.Errorf("query after SetRaw: %+v", u.Query)
+	}
+
+	u.SetQuery(nil)
+	if u.Raw != "https://example.com/search#results" {
+		t.Errorf("Raw without query = %q", u.Raw)
+	}
+}
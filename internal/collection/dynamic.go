@@ -0,0 +1,186 @@
+package collection
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDynamicVariables generates the dynamic variables of sent requests
+var DefaultDynamicVariables = NewDynamicVariables(uint64(time.Now().UnixNano()), time.Now)
+
+// DynamicVariables generates the values of Postman's built-in dynamic
+// variables such as {{$guid}} or {{$timestamp}}. Every placeholder gets a
+// fresh value. It is safe for concurrent use.
+type DynamicVariables struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+	now  func() time.Time
+}
+
+// NewDynamicVariables returns a generator whose random values are determined
+// by seed and whose time based values are taken from now
+func NewDynamicVariables(seed uint64, now func() time.Time) *DynamicVariables {
+	return &DynamicVariables{
+		rand: rand.New(rand.NewPCG(seed, seed)),
+		now:  now,
+	}
+}
+
+// dynamicGenerators maps the supported dynamic variable names to their generators
+var dynamicGenerators = map[string]func(d *DynamicVariables) string{
+	"$guid":       (*DynamicVariables).uuid,
+	"$randomUUID": (*DynamicVariables).uuid,
+	"$timestamp": func(d *DynamicVariables) string {
+		return strconv.FormatInt(d.now().Unix(), 10)
+	},
+	"$isoTimestamp": func(d *DynamicVariables) string {
+		return d.now().UTC().Format("2006-01-02T15:04:05.000Z")
+	},
+	"$randomInt": func(d *DynamicVariables) string {
+		return strconv.Itoa(d.rand.IntN(1001))
+	},
+	"$randomBoolean": func(d *DynamicVariables) string {
+		return strconv.FormatBool(d.rand.IntN(2) == 1)
+	},
+	"$randomAlphaNumeric": func(d *DynamicVariables) string {
+		return d.alphaNumeric(1)
+	},
+	"$randomPassword": func(d *DynamicVariables) string {
+		return d.alphaNumeric(15)
+	},
+	"$randomHexColor": func(d *DynamicVariables) string {
+		return fmt.Sprintf("#%06x", d.rand.IntN(1<<24))
+	},
+	"$randomIP": func(d *DynamicVariables) string {
+		return fmt.Sprintf("%d.%d.%d.%d", d.rand.IntN(256), d.rand.IntN(256), d.rand.IntN(256), d.rand.IntN(256))
+	},
+	"$randomIPV6": func(d *DynamicVariables) string {
+		groups := make([]string, 8)
+		for i := range groups {
+			groups[i] = fmt.Sprintf("%x", d.rand.IntN(1<<16))
+		}
+		return strings.Join(groups, ":")
+	},
+	"$randomFirstName": func(d *DynamicVariables) string {
+		return d.pick(firstNames)
+	},
+	"$randomLastName": func(d *DynamicVariables) string {
+		return d.pick(lastNames)
+	},
+	"$randomFullName": func(d *DynamicVariables) string {
+		return d.pick(firstNames) + " " + d.pick(lastNames)
+	},
+	"$randomUserName": func(d *DynamicVariables) string {
+		return d.userName()
+	},
+	"$randomEmail": func(d *DynamicVariables) string {
+		return d.userName() + "@" + d.pick(emailDomains)
+	},
+	"$randomWord": func(d *DynamicVariables) string {
+		return d.pick(words)
+	},
+	"$randomDomainName": func(d *DynamicVariables) string {
+		return d.pick(words) + "-" + d.pick(words) + "." + d.pick(topLevelDomains)
+	},
+	"$randomUrl": func(d *DynamicVariables) string {
+		return "https://" + d.pick(words) + "-" + d.pick(words) + "." + d.pick(topLevelDomains)
+	},
+	"$randomPhoneNumber": func(d *DynamicVariables) string {
+		return fmt.Sprintf("%03d-%03d-%04d", 200+d.rand.IntN(800), d.rand.IntN(1000), d.rand.IntN(10000))
+	},
+	"$randomCity": func(d *DynamicVariables) string {
+		return d.pick(cities)
+	},
+	"$randomCountryCode": func(d *DynamicVariables) string {
+		return d.pick(countryCodes)
+	},
+	"$randomColor": func(d *DynamicVariables) string {
+		return d.pick(colors)
+	},
+	"$randomDatePast": func(d *DynamicVariables) string {
+		return d.now().Add(-d.duration(365 * 24 * time.Hour)).UTC().Format(time.RFC1123)
+	},
+	"$randomDateFuture": func(d *DynamicVariables) string {
+		return d.now().Add(d.duration(365 * 24 * time.Hour)).UTC().Format(time.RFC1123)
+	},
+	"$randomDateRecent": func(d *DynamicVariables) string {
+		return d.now().Add(-d.duration(24 * time.Hour)).UTC().Format(time.RFC1123)
+	},
+}
+
+// IsDynamicVariable reports whether name is a supported dynamic variable
+func IsDynamicVariable(name string) bool {
+	_, ok := dynamicGenerators[name]
+	return ok
+}
+
+// Value returns a fresh value of the dynamic variable name, e.g. "$guid"
+func (d *DynamicVariables) Value(name string) (string, bool) {
+	gen, ok := dynamicGenerators[name]
+	if !ok {
+		return "", false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return gen(d), true
+}
+
+// Substitute replaces each dynamic variable placeholder in s with a fresh
+// value. Other placeholders are left as they are.
+func (d *DynamicVariables) Substitute(s string) string {
+	return placeholderRe.ReplaceAllStringFunc(s, func(placeholder string) string {
+		if value, ok := d.Value(placeholder[2 : len(placeholder)-2]); ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// uuid returns a random version 4 UUID
+func (d *DynamicVariables) uuid() string {
+	var b [16]byte
+	for i := range b {
+		b[i] = byte(d.rand.Uint32())
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+const alphaNumeric = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+func (d *DynamicVariables) alphaNumeric(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphaNumeric[d.rand.IntN(len(alphaNumeric))]
+	}
+	return string(b)
+}
+
+func (d *DynamicVariables) userName() string {
+	return strings.ToLower(d.pick(firstNames)) + "." + strings.ToLower(d.pick(lastNames)) + strconv.Itoa(d.rand.IntN(100))
+}
+
+func (d *DynamicVariables) pick(values []string) string {
+	return values[d.rand.IntN(len(values))]
+}
+
+// duration returns a random duration shorter than limit
+func (d *DynamicVariables) duration(limit time.Duration) time.Duration {
+	return time.Duration(d.rand.Int64N(int64(limit)))
+}
+
+var (
+	firstNames      = []string{"Alice", "Bob", "Carol", "David", "Emma", "Frank", "Grace", "Henry", "Irene", "Jack", "Kate", "Liam", "Mia", "Noah", "Olga", "Peter"}
+	lastNames       = []string{"Smith", "Johnson", "Brown", "Taylor", "Miller", "Wilson", "Moore", "Clark", "Walker", "Young", "King", "Wright", "Scott", "Green"}
+	emailDomains    = []string{"example.com", "example.org", "example.net"}
+	topLevelDomains = []string{"com", "org", "net", "io", "info"}
+	words           = []string{"alpha", "bridge", "cloud", "delta", "ember", "forest", "garden", "harbor", "island", "jungle", "kernel", "lemon", "meadow", "nebula", "orbit", "pixel", "quartz", "river", "signal", "timber"}
+	cities          = []string{"Amsterdam", "Berlin", "Chicago", "Dublin", "Helsinki", "Lisbon", "London", "Madrid", "Oslo", "Paris", "Prague", "Tokyo", "Toronto", "Vienna"}
+	countryCodes    = []string{"AT", "BR", "CA", "CZ", "DE", "ES", "FI", "FR", "GB", "IE", "JP", "NL", "NO", "PT", "US"}
+	colors          = []string{"black", "blue", "cyan", "gold", "green", "grey", "magenta", "orange", "pink", "purple", "red", "silver", "white", "yellow"}
+)
@@ -0,0 +1,72 @@
+package collection
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func fixedClock() time.Time {
+	return time.Date(2024, 3, 1, 12, 30, 45, 123000000, time.UTC)
+}
+
+func TestDynamicVariables_Value(t *testing.T) {
+	d := NewDynamicVariables(1, fixedClock)
+
+	tests := []struct {
+		name    string
+		pattern string
+	}{
+		{"$guid", `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{"$randomUUID", `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{"$timestamp", `^1709296245$`},
+		{"$isoTimestamp", `^2024-03-01T12:30:45\.123Z$`},
+		{"$randomInt", `^\d{1,4}$`},
+		{"$randomBoolean", `^(true|false)$`},
+		{"$randomEmail", `^[a-z]+\.[a-z]+\d+@example\.(com|org|net)$`},
+		{"$randomHexColor", `^#[0-9a-f]{6}$`},
+		{"$randomIP", `^\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}$`},
+		{"$randomPassword", `^[a-zA-Z0-9]{15}$`},
+		{"$randomPhoneNumber", `^\d{3}-\d{3}-\d{4}$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := d.Value(tt.name)
+			if !ok {
+				t.Fatalf("Value(%q) is not defined", tt.name)
+			}
+			if !regexp.MustCompile(tt.pattern).MatchString(got) {
+				t.Errorf("Value(%q) = %q, want match of %s", tt.name, got, tt.pattern)
+			}
+		})
+	}
+
+	if _, ok := d.Value("$unknown"); ok {
+		t.Errorf("Value(\"$unknown\") should not be defined")
+	}
+}
+
+func TestDynamicVariables_Substitute(t *testing.T) {
+	input := `{"id":"{{$guid}}","other":"{{$guid}}","n":{{$randomInt}},"user":"{{user}}","x":"{{$unknown}}"}`
+
+	got := NewDynamicVariables(42, fixedClock).Substitute(input)
+	if again := NewDynamicVariables(42, fixedClock).Substitute(input); got != again {
+		t.Errorf("Substitute() with the same seed differs: %q and %q", got, again)
+	}
+
+	re := regexp.MustCompile(`^\{"id":"([0-9a-f-]{36})","other":"([0-9a-f-]{36})","n":\d+,"user":"\{\{user\}\}","x":"\{\{\$unknown\}\}"\}$`)
+	m := re.FindStringSubmatch(got)
+	if m == nil {
+		t.Fatalf("Substitute() = %q", got)
+	}
+	if m[1] == m[2] {
+		t.Errorf("Substitute() should give every placeholder a fresh value, got %q twice", m[1])
+	}
+}
+
+func TestUnresolvedVariablesIgnoresDynamic(t *testing.T) {
+	got := UnresolvedVariables("{{$guid}} {{$timestamp}} {{$nope}}", nil)
+	if len(got) != 1 || got[0] != "$nope" {
+		t.Errorf("UnresolvedVariables() = %v, want [$nope]", got)
+	}
+}
@@ -0,0 +1,53 @@
+package collection
+
+import "encoding/json"
+
+// Event is a script hook such as "prerequest" or "test". GHOSTman does not
+// run scripts but keeps them so they survive a save.
+type Event struct {
+	ID       string  `json:"id,omitempty"`
+	Listen   string  `json:"listen"`
+	Script   *Script `json:"script,omitempty"`
+	Disabled bool    `json:"disabled,omitempty"`
+
+	extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (e *Event) UnmarshalJSON(data []byte) error {
+	type event Event
+	extra, err := decodeObject(data, (*event)(e))
+	e.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler
+func (e Event) MarshalJSON() ([]byte, error) {
+	type event Event
+	return encodeObject((*event)(&e), e.extra)
+}
+
+// Script is the source of an event script
+type Script struct {
+	ID   string          `json:"id,omitempty"`
+	Type string          `json:"type,omitempty"`
+	Exec json.RawMessage `json:"exec,omitempty"`
+	Src  json.RawMessage `json:"src,omitempty"`
+	Name string          `json:"name,omitempty"`
+
+	extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *Script) UnmarshalJSON(data []byte) error {
+	type script Script
+	extra, err := decodeObject(data, (*script)(s))
+	s.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler
+func (s Script) MarshalJSON() ([]byte, error) {
+	type script Script
+	return encodeObject((*script)(&s), s.extra)
+}
@@ -0,0 +1,244 @@
+package collection
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// decodeObject unmarshals data into v, which must be a pointer to a struct type
+// without its own UnmarshalJSON, and returns the members of the JSON object that
+// the struct does not model, so they can be written back unchanged.
+func decodeObject(data []byte, v any) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for _, name := range jsonFields(reflect.TypeOf(v).Elem()) {
+		delete(members, name)
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	return members, nil
+}
+
+// encodeObject marshals v, which must be a pointer to a struct type without its
+// own MarshalJSON, and appends the extra members after the modeled ones.
+func encodeObject(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for i, k := range keys {
+		if i > 0 || len(data) > 2 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(extra[k])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshal is json.Marshal without HTML escaping, so that bodies and scripts
+// are written back the way Postman writes them.
+func marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// jsonFields returns the JSON member names of the struct fields of t
+func jsonFields(t reflect.Type) []string {
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// isJSONString reports whether data holds a JSON string
+func isJSONString(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '"'
+}
+
+// isJSONObject reports whether data holds a JSON object
+func isJSONObject(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+// StringList is a list of strings that also accepts a single JSON string and
+// list elements of the form {"value": "..."} as used by Postman URL paths.
+type StringList []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	if isJSONString(data) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*l = StringList{s}
+		return nil
+	}
+
+	var elems []json.RawMessage
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	list := make(StringList, 0, len(elems))
+	for _, e := range elems {
+		if isJSONObject(e) {
+			var obj struct {
+				Value string `json:"value"`
+			}
+			if err := json.Unmarshal(e, &obj); err != nil {
+				return err
+			}
+			list = append(list, obj.Value)
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(e, &s); err != nil {
+			return err
+		}
+		list = append(list, s)
+	}
+	*l = list
+	return nil
+}
+
+// listObjects returns the object elements of the JSON array data by index,
+// nil when there are none
+func listObjects(data []byte) []json.RawMessage {
+	var elems []json.RawMessage
+	if json.Unmarshal(data, &elems) != nil {
+		return nil
+	}
+	var objects []json.RawMessage
+	for i, e := range elems {
+		if !isJSONObject(e) {
+			continue
+		}
+		if objects == nil {
+			objects = make([]json.RawMessage, len(elems))
+		}
+		objects[i] = e
+	}
+	return objects
+}
+
+// encodeList returns the JSON array of l, nil when it is empty. The elements
+// of objects are written back in place of the strings equal to their value.
+func encodeList(l StringList, objects []json.RawMessage) (json.RawMessage, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	elems := make([]json.RawMessage, len(l))
+	for i, s := range l {
+		if i < len(objects) && objects[i] != nil {
+			var obj struct {
+				Value string `json:"value"`
+			}
+			if json.Unmarshal(objects[i], &obj) == nil && obj.Value == s {
+				elems[i] = objects[i]
+				continue
+			}
+		}
+		data, err := marshal(s)
+		if err != nil {
+			return nil, err
+		}
+		elems[i] = data
+	}
+	return marshal(elems)
+}
+
+// Description is a Postman description, which is either a plain string or an
+// object with content, type and version.
+type Description struct {
+	Content string
+	Type    string
+	Version json.RawMessage
+	object  bool
+}
+
+// String returns the description text
+func (d Description) String() string {
+	return d.Content
+}
+
+// IsZero reports whether the description is empty, for omitzero
+func (d Description) IsZero() bool {
+	return d.Content == "" && !d.object
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *Description) UnmarshalJSON(data []byte) error {
+	if !isJSONObject(data) {
+		*d = Description{}
+		if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+			return nil
+		}
+		return json.Unmarshal(data, &d.Content)
+	}
+	var obj struct {
+		Content string          `json:"content"`
+		Type    string          `json:"type"`
+		Version json.RawMessage `json:"version"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*d = Description{Content: obj.Content, Type: obj.Type, Version: obj.Version, object: true}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (d Description) MarshalJSON() ([]byte, error) {
+	if !d.object {
+		return marshal(d.Content)
+	}
+	return marshal(struct {
+		Content string          `json:"content"`
+		Type    string          `json:"type,omitempty"`
+		Version json.RawMessage `json:"version,omitempty"`
+	}{d.Content, d.Type, d.Version})
+}
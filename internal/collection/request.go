@@ -0,0 +1,476 @@
+package collection
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// Request describes an HTTP request of a collection item.
+// Postman also allows a request to be a bare URL string.
+type Request struct {
+	Method      string      `json:"method,omitempty"`
+	Description Description `json:"description,omitzero"`
+	Header      HeaderList  `json:"header,omitempty"`
+	Body        *Body       `json:"body,omitempty"`
+	URL         URL         `json:"url,omitzero"`
+	Auth        *Auth       `json:"auth,omitempty"`
+
+	extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *Request) UnmarshalJSON(data []byte) error {
+	if isJSONString(data) {
+		*r = Request{}
+		return json.Unmarshal(data, &r.URL)
+	}
+	type request Request
+	extra, err := decodeObject(data, (*request)(r))
+	r.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler
+func (r Request) MarshalJSON() ([]byte, error) {
+	type request Request
+	return encodeObject((*request)(&r), r.extra)
+}
+
+// URL describes the request URL. Raw is the source of truth, the other
+// members are its parsed parts. Postman also allows a bare URL string.
+type URL struct {
+	Raw      string       `json:"raw,omitempty"`
+	Protocol string       `json:"protocol,omitempty"`
+	Host     StringList   `json:"host,omitempty"`
+	Port     string       `json:"port,omitempty"`
+	Path     StringList   `json:"path,omitempty"`
+	Query    []QueryParam `json:"query,omitempty"`
+	Hash     string       `json:"hash,omitempty"`
+	Variable []Variable   `json:"variable,omitempty"`
+
+	// pathObjects are the path segments of the form {"value": "..."} by
+	// index, written back while the segment is unchanged
+	pathObjects []json.RawMessage
+	extra       map[string]json.RawMessage
+}
+
+// urlJSON is the wire form of URL with the path kept as JSON
+type urlJSON struct {
+	Raw      string          `json:"raw,omitempty"`
+	Protocol string          `json:"protocol,omitempty"`
+	Host     StringList      `json:"host,omitempty"`
+	Port     string          `json:"port,omitempty"`
+	Path     json.RawMessage `json:"path,omitempty"`
+	Query    []QueryParam    `json:"query,omitempty"`
+	Hash     string          `json:"hash,omitempty"`
+	Variable []Variable      `json:"variable,omitempty"`
+}
+
+// IsZero reports whether the URL is empty, for omitzero
+func (u URL) IsZero() bool {
+	return u.Raw == "" && u.Protocol == "" && len(u.Host) == 0 && u.Port == "" &&
+		len(u.Path) == 0 && len(u.Query) == 0 && u.Hash == "" && len(u.Variable) == 0 && len(u.extra) == 0
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (u *URL) UnmarshalJSON(data []byte) error {
+	if isJSONString(data) {
+		*u = URL{}
+		return json.Unmarshal(data, &u.Raw)
+	}
+	type url URL
+	extra, err := decodeObject(data, (*url)(u))
+	if err != nil {
+		return err
+	}
+	var members struct {
+		Path json.RawMessage `json:"path"`
+	}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	u.pathObjects, u.extra = listObjects(members.Path), extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (u URL) MarshalJSON() ([]byte, error) {
+	path, err := encodeList(u.Path, u.pathObjects)
+	if err != nil {
+		return nil, err
+	}
+	return encodeObject(&urlJSON{
+		Raw:      u.Raw,
+		Protocol: u.Protocol,
+		Host:     u.Host,
+		Port:     u.Port,
+		Path:     path,
+		Query:    u.Query,
+		Hash:     u.Hash,
+		Variable: u.Variable,
+	}, u.extra)
+}
+
+// SetRaw sets the raw URL and re-derives the parsed parts from it the way
+// Postman does. Disabled query parameters are not part of the raw URL and
+// are kept, as are the descriptions of parameters that are still present.
+// The :name path segments are the path variables, keeping their values.
+func (u *URL) SetRaw(raw string) {
+	old, oldVariables := u.Query, u.Variable
+	u.Raw = raw
+	u.Protocol, u.Host, u.Port, u.Path, u.Query, u.Hash = "", nil, "", nil, nil, ""
+
+	rest := raw
+	if protocol, after, ok := strings.Cut(rest, "://"); ok {
+		u.Protocol, rest = protocol, after
+	}
+	if before, hash, ok := strings.Cut(rest, "#"); ok {
+		rest, u.Hash = before, hash
+	}
+	var query string
+	rest, query, hasQuery := strings.Cut(rest, "?")
+
+	host, path, hasPath := strings.Cut(rest, "/")
+	// a host that is a single variable may hold a port, e.g. {{base}}
+	singleVariable := strings.HasPrefix(host, "{{") && strings.HasSuffix(host, "}}") && strings.Count(host, "{{") == 1
+	if !singleVariable {
+		if h, port, ok := strings.Cut(host, ":"); ok {
+			host, u.Port = h, port
+		}
+	}
+	if host != "" {
+		u.Host = strings.Split(host, ".")
+	}
+	if hasPath {
+		u.Path = strings.Split(path, "/")
+	}
+
+	if hasQuery {
+		for _, pair := range strings.Split(query, "&") {
+			if pair == "" {
+				continue
+			}
+			key, value, _ := strings.Cut(pair, "=")
+			param := QueryParam{Key: key, Value: value}
+			for _, o := range old {
+				if o.Key == key && !o.Disabled {
+					param.Description, param.extra = o.Description, o.extra
+					break
+				}
+			}
+			u.Query = append(u.Query, param)
+		}
+	}
+	for _, o := range old {
+		if o.Disabled {
+			u.Query = append(u.Query, o)
+		}
+	}
+
+	u.Variable = nil
+	for _, segment := range u.Path {
+		name, ok := strings.CutPrefix(segment, ":")
+		if !ok || name == "" || slices.ContainsFunc(u.Variable, func(v Variable) bool { return v.Key == name }) {
+			continue
+		}
+		variable := Variable{Key: name}
+		for _, o := range oldVariables {
+			if o.Key == name {
+				variable = o
+				break
+			}
+		}
+		u.Variable = append(u.Variable, variable)
+	}
+}
+
+// queryDelimiters escapes the characters delimiting query parameters
+var queryDelimiters = strings.NewReplacer("&", "%26", "#", "%23")
+
+// SetQuery sets the query parameters and rewrites the query of the raw URL
+// with the enabled ones. Characters delimiting the parameters are escaped in
+// their keys and values, which are otherwise kept as they are.
+func (u *URL) SetQuery(query []QueryParam) {
+	u.Query = make([]QueryParam, len(query))
+	var pairs []string
+	for i, q := range query {
+		q.Key = strings.ReplaceAll(queryDelimiters.Replace(q.Key), "=", "%3D")
+		q.Value = queryDelimiters.Replace(q.Value)
+		u.Query[i] = q
+		if q.Disabled {
+			continue
+		}
+		if q.Value == "" {
+			pairs = append(pairs, q.Key)
+		} else {
+			pairs = append(pairs, q.Key+"="+q.Value)
+		}
+	}
+
+	base, hash, hasHash := strings.Cut(u.Raw, "#")
+	base, _, _ = strings.Cut(base, "?")
+	if len(pairs) > 0 {
+		base += "?" + strings.Join(pairs, "&")
+	}
+	if hasHash {
+		base += "#" + hash
+	}
+	u.Raw = base
+}
+
+// QueryParam is a URL query parameter or an x-www-form-urlencoded body field
+type QueryParam struct {
+	Key         string      `json:"key"`
+	Value       string      `json:"value"`
+	Disabled    bool        `json:"disabled,omitempty"`
+	Description Description `json:"description,omitzero"`
+
+	extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (q *QueryParam) UnmarshalJSON(data []byte) error {
+	type queryParam QueryParam
+	extra, err := decodeObject(data, (*queryParam)(q))
+	q.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler
+func (q QueryParam) MarshalJSON() ([]byte, error) {
+	type queryParam QueryParam
+	return encodeObject((*queryParam)(&q), q.extra)
+}
+
+// Header is a single request or response header
+type Header struct {
+	Key         string      `json:"key"`
+	Value       string      `json:"value"`
+	Disabled    bool        `json:"disabled,omitempty"`
+	Description Description `json:"description,omitzero"`
+
+	extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (h *Header) UnmarshalJSON(data []byte) error {
+	type header Header
+	extra, err := decodeObject(data, (*header)(h))
+	h.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler
+func (h Header) MarshalJSON() ([]byte, error) {
+	type header Header
+	return encodeObject((*header)(&h), h.extra)
+}
+
+// HeaderList is a list of headers. Postman also allows the headers to be
+// a single string of "Key: Value" lines.
+type HeaderList []Header
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *HeaderList) UnmarshalJSON(data []byte) error {
+	if !isJSONString(data) {
+		return json.Unmarshal(data, (*[]Header)(l))
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	var list HeaderList
+	for _, line := range strings.Split(s, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		list = append(list, Header{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)})
+	}
+	*l = list
+	return nil
+}
+
+// Body is the request body in one of the Postman body modes
+type Body struct {
+	Mode       string          `json:"mode,omitempty"`
+	Raw        string          `json:"raw,omitempty"`
+	URLEncoded []QueryParam    `json:"urlencoded,omitempty"`
+	FormData   []FormParam     `json:"formdata,omitempty"`
+	File       *BodyFile       `json:"file,omitempty"`
+	GraphQL    *GraphQL        `json:"graphql,omitempty"`
+	Options    json.RawMessage `json:"options,omitempty"`
+	Disabled   bool            `json:"disabled,omitempty"`
+
+	extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *Body) UnmarshalJSON(data []byte) error {
+	type body Body
+	extra, err := decodeObject(data, (*body)(b))
+	b.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler
+func (b Body) MarshalJSON() ([]byte, error) {
+	type body Body
+	extra := b.extra
+	if b.Mode == "raw" && b.Raw == "" {
+		// Postman writes an empty raw body for raw mode
+		extra = make(map[string]json.RawMessage, len(b.extra)+1)
+		for k, v := range b.extra {
+			extra[k] = v
+		}
+		extra["raw"] = json.RawMessage(`""`)
+	}
+	return encodeObject((*body)(&b), extra)
+}
+
+// RawLanguage returns the language of a raw body set in its options, e.g.
+// "json" or "xml", empty when not set
+func (b *Body) RawLanguage() string {
+	var options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	}
+	if len(b.Options) == 0 || json.Unmarshal(b.Options, &options) != nil {
+		return ""
+	}
+	return options.Raw.Language
+}
+
+// SetRawLanguage sets the language of a raw body, keeping the other options
+func (b *Body) SetRawLanguage(language string) error {
+	if language == b.RawLanguage() {
+		return nil
+	}
+	options := make(map[string]json.RawMessage)
+	if len(b.Options) > 0 {
+		if err := json.Unmarshal(b.Options, &options); err != nil {
+			return err
+		}
+	}
+	raw := make(map[string]json.RawMessage)
+	if len(options["raw"]) > 0 {
+		if err := json.Unmarshal(options["raw"], &raw); err != nil {
+			return err
+		}
+	}
+	lang, err := marshal(language)
+	if err != nil {
+		return err
+	}
+	raw["language"] = lang
+	if options["raw"], err = marshal(raw); err != nil {
+		return err
+	}
+	b.Options, err = marshal(options)
+	return err
+}
+
+// FormParam is a multipart/form-data body field, either text or file
+type FormParam struct {
+	Key         string          `json:"key"`
+	Value       string          `json:"value,omitempty"`
+	Src         json.RawMessage `json:"src,omitempty"`
+	Type        string          `json:"type,omitempty"`
+	ContentType string          `json:"contentType,omitempty"`
+	Disabled    bool            `json:"disabled,omitempty"`
+	Description Description     `json:"description,omitzero"`
+
+	extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (f *FormParam) UnmarshalJSON(data []byte) error {
+	type formParam FormParam
+	extra, err := decodeObject(data, (*formParam)(f))
+	f.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler
+func (f FormParam) MarshalJSON() ([]byte, error) {
+	type formParam FormParam
+	return encodeObject((*formParam)(&f), f.extra)
+}
+
+// Files returns the paths of the files of a file field. Postman stores a
+// single path or a list of paths.
+func (f FormParam) Files() []string {
+	var path string
+	if json.Unmarshal(f.Src, &path) == nil {
+		if path == "" {
+			return nil
+		}
+		return []string{path}
+	}
+	var paths []string
+	json.Unmarshal(f.Src, &paths)
+	return paths
+}
+
+// SetFiles sets the paths of the files of a file field
+func (f *FormParam) SetFiles(paths []string) error {
+	if slices.Equal(paths, f.Files()) {
+		return nil
+	}
+	var err error
+	switch len(paths) {
+	case 0:
+		f.Src = nil
+	case 1:
+		f.Src, err = marshal(paths[0])
+	default:
+		f.Src, err = marshal(paths)
+	}
+	return err
+}
+
+// BodyFile is the body of a request in file mode
+type BodyFile struct {
+	Src     string `json:"src,omitempty"`
+	Content string `json:"content,omitempty"`
+
+	extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (f *BodyFile) UnmarshalJSON(data []byte) error {
+	type bodyFile BodyFile
+	extra, err := decodeObject(data, (*bodyFile)(f))
+	f.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler
+func (f BodyFile) MarshalJSON() ([]byte, error) {
+	type bodyFile BodyFile
+	return encodeObject((*bodyFile)(&f), f.extra)
+}
+
+// GraphQL is the body of a request in graphql mode
+type GraphQL struct {
+	Query     string `json:"query"`
+	Variables string `json:"variables,omitempty"`
+
+	extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (g *GraphQL) UnmarshalJSON(data []byte) error {
+	type graphQL GraphQL
+	extra, err := decodeObject(data, (*graphQL)(g))
+	g.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler
+func (g GraphQL) MarshalJSON() ([]byte, error) {
+	type graphQL GraphQL
+	return encodeObject((*graphQL)(&g), g.extra)
+}
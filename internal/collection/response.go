@@ -0,0 +1,75 @@
+package collection
+
+import "encoding/json"
+
+// Response is an example response saved with a request.
+// Anything that does not decode as a response object is kept as it is.
+type Response struct {
+	ID              string          `json:"id,omitempty"`
+	Name            string          `json:"name,omitempty"`
+	OriginalRequest *Request        `json:"originalRequest,omitempty"`
+	ResponseTime    json.RawMessage `json:"responseTime,omitempty"`
+	Timings         json.RawMessage `json:"timings,omitempty"`
+	Header          HeaderList      `json:"header,omitempty"`
+	Cookie          []Cookie        `json:"cookie,omitempty"`
+	Body            string          `json:"body,omitempty"`
+	Status          string          `json:"status,omitempty"`
+	Code            int             `json:"code,omitempty"`
+
+	raw   json.RawMessage
+	extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type response Response
+	var resp response
+	extra, err := decodeObject(data, &resp)
+	if !isJSONObject(data) || err != nil {
+		*r = Response{raw: append(json.RawMessage(nil), data...)}
+		return nil
+	}
+	*r = Response(resp)
+	r.extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (r Response) MarshalJSON() ([]byte, error) {
+	if r.raw != nil {
+		return r.raw, nil
+	}
+	type response Response
+	return encodeObject((*response)(&r), r.extra)
+}
+
+// Cookie is a cookie of an example response
+type Cookie struct {
+	Domain     string `json:"domain"`
+	Path       string `json:"path"`
+	Name       string `json:"name,omitempty"`
+	Value      string `json:"value,omitempty"`
+	Expires    any    `json:"expires,omitempty"`
+	MaxAge     string `json:"maxAge,omitempty"`
+	HostOnly   bool   `json:"hostOnly,omitempty"`
+	HTTPOnly   bool   `json:"httpOnly,omitempty"`
+	Secure     bool   `json:"secure,omitempty"`
+	Session    bool   `json:"session,omitempty"`
+	Extensions []any  `json:"extensions,omitempty"`
+
+	extra map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (c *Cookie) UnmarshalJSON(data []byte) error {
+	type cookie Cookie
+	extra, err := decodeObject(data, (*cookie)(c))
+	c.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler
+func (c Cookie) MarshalJSON() ([]byte, error) {
+	type cookie Cookie
+	return encodeObject((*cookie)(&c), c.extra)
+}
@@ -0,0 +1,206 @@
+package collection
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrRequestNotFound is returned when the item path does not lead to a request
+var ErrRequestNotFound = errors.New("request not found in collection")
+
+// SaveRequest writes req into the collection file at path as the request of
+// the item found by following itemPath, a list of item indexes from the root.
+// The rest of the file is left byte for byte as it was.
+func SaveRequest(path string, itemPath []int, req *Request) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading Postman collection: %v", err)
+	}
+	data, err = ReplaceRequest(data, itemPath, req)
+	if err != nil {
+		return fmt.Errorf("error updating Postman collection: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("error writing Postman collection: %v", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error writing Postman collection: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing Postman collection: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing Postman collection: %v", err)
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("error writing Postman collection: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing Postman collection: %v", err)
+	}
+	return nil
+}
+
+// ReplaceRequest returns data with the request of the item found by following
+// itemPath replaced by req. Only the bytes of that request change; the new
+// request is indented to match the surrounding document unless the document
+// is on a single line.
+func ReplaceRequest(data []byte, itemPath []int, req *Request) ([]byte, error) {
+	if len(itemPath) == 0 {
+		return nil, ErrRequestNotFound
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	keys := make([]string, 0, 2*len(itemPath)+1)
+	for range itemPath {
+		keys = append(keys, "item", "")
+	}
+	keys = append(keys, "request")
+
+	start, end, err := findValue(dec, data, keys, itemPath)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(data, '\n') >= 0 {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, encoded, lineIndent(data, start), indentUnit(data)); err != nil {
+			return nil, err
+		}
+		encoded = indented.Bytes()
+	}
+
+	out := make([]byte, 0, len(data)-(end-start)+len(encoded))
+	out = append(out, data[:start]...)
+	out = append(out, encoded...)
+	out = append(out, data[end:]...)
+	return out, nil
+}
+
+// findValue walks the document along keys, where "" selects the next element
+// of indexes in an array, and returns the byte range of the value reached.
+func findValue(dec *json.Decoder, data []byte, keys []string, indexes []int) (int, int, error) {
+	for _, key := range keys {
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if key == "" {
+			if tok != json.Delim('[') {
+				return 0, 0, ErrRequestNotFound
+			}
+			idx := indexes[0]
+			indexes = indexes[1:]
+			for i := 0; i < idx; i++ {
+				if !dec.More() {
+					return 0, 0, ErrRequestNotFound
+				}
+				if err := skipValue(dec); err != nil {
+					return 0, 0, err
+				}
+			}
+			if !dec.More() {
+				return 0, 0, ErrRequestNotFound
+			}
+			continue
+		}
+
+		if tok != json.Delim('{') {
+			return 0, 0, ErrRequestNotFound
+		}
+		if err := findMember(dec, key); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	start := valueStart(data, int(dec.InputOffset()))
+	if err := skipValue(dec); err != nil {
+		return 0, 0, err
+	}
+	return start, int(dec.InputOffset()), nil
+}
+
+// findMember advances dec inside an object to the value of the member key
+func findMember(dec *json.Decoder, key string) error {
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if tok == key {
+			return nil
+		}
+		if err := skipValue(dec); err != nil {
+			return err
+		}
+	}
+	return ErrRequestNotFound
+}
+
+// skipValue consumes the next value of dec
+func skipValue(dec *json.Decoder) error {
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		if err == io.EOF {
+			return ErrRequestNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// valueStart returns the offset of the value following offset, skipping the
+// name separator and white space
+func valueStart(data []byte, offset int) int {
+	for offset < len(data) && strings.IndexByte(" \t\r\n:,", data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// lineIndent returns the leading white space of the line containing offset
+func lineIndent(data []byte, offset int) string {
+	lineStart := bytes.LastIndexByte(data[:offset], '\n') + 1
+	end := lineStart
+	for end < offset && (data[end] == ' ' || data[end] == '\t') {
+		end++
+	}
+	return string(data[lineStart:end])
+}
+
+// indentUnit guesses the indentation step of the document from its first
+// indented line, defaulting to a tab as written by Postman
+func indentUnit(data []byte) string {
+	for i := bytes.IndexByte(data, '\n'); i >= 0 && i+1 < len(data); {
+		line := data[i+1:]
+		end := 0
+		for end < len(line) && (line[end] == ' ' || line[end] == '\t') {
+			end++
+		}
+		if end > 0 {
+			return string(line[:end])
+		}
+		next := bytes.IndexByte(line, '\n')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return "\t"
+}
@@ -0,0 +1,108 @@
+package collection
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const savedCollection = `{
+	"info": {
+		"name": "Test"
+	},
+	"item": [
+		{
+			"name": "Folder",
+			"item": [
+				{
+					"name": "Get user",
+					"request": {
+						"method": "GET",
+						"header": [],
+						"url": {
+							"raw": "{{base}}/users/1",
+							"host": ["{{base}}"],
+							"path": ["users", "1"]
+						}
+					}
+				}
+			]
+		},
+		{
+			"name": "Health",
+			"request": {"method": "GET", "url": "{{base}}/health"}
+		}
+	],
+	"variable": [{"key": "base", "value": "http://localhost"}]
+}`
+
+func TestReplaceRequest(t *testing.T) {
+	req := &Request{Method: "POST", Header: HeaderList{{Key: "Accept", Value: "{{accept}}"}}}
+	req.URL.SetRaw("{{base}}/users/2")
+
+	out, err := ReplaceRequest([]byte(savedCollection), []int{0, 0}, req)
+	if err != nil {
+		t.Fatalf("ReplaceRequest error: %v", err)
+	}
+
+	start := strings.Index(savedCollection, `"request": {`) + len(`"request": `)
+	end := strings.Index(savedCollection, "\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t]") + len("\n\t\t\t\t\t}")
+	if !strings.HasPrefix(string(out), savedCollection[:start]) || !strings.HasSuffix(string(out), savedCollection[end:]) {
+		t.Errorf("bytes outside the request changed:\n%s", out)
+	}
+	if !strings.Contains(string(out), "\n\t\t\t\t\t\t\"method\": \"POST\",\n") {
+		t.Errorf("request is not indented like the document:\n%s", out)
+	}
+
+	var coll Collection
+	if err := json.Unmarshal(out, &coll); err != nil {
+		t.Fatalf("result is not a collection: %v", err)
+	}
+	got := coll.Item[0].Item[0].Request
+	if got.Method != "POST" || got.URL.Raw != "{{base}}/users/2" || got.Header[0].Value != "{{accept}}" {
+		t.Errorf("unexpected request: %+v", got)
+	}
+	if coll.Item[1].Request.URL.Raw != "{{base}}/health" {
+		t.Errorf("other request changed: %+v", coll.Item[1].Request)
+	}
+}
+
+func TestReplaceRequestNotFound(t *testing.T) {
+	for _, path := range [][]int{nil, {5}, {0, 3}, {1, 0}} {
+		if _, err := ReplaceRequest([]byte(savedCollection), path, &Request{}); err == nil {
+			t.Errorf("expected error for item path %v", path)
+		}
+	}
+}
+
+func TestSaveRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "col.json")
+	if err := os.WriteFile(path, []byte(savedCollection), 0o600); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	if err := SaveRequest(path, []int{1}, &Request{Method: "HEAD", URL: URL{Raw: "{{base}}/health"}}); err != nil {
+		t.Fatalf("SaveRequest error: %v", err)
+	}
+
+	coll, err := LoadPostmanCollection(path)
+	if err != nil {
+		t.Fatalf("LoadPostmanCollection error: %v", err)
+	}
+	if coll.Item[1].Request.Method != "HEAD" {
+		t.Errorf("request not saved: %+v", coll.Item[1].Request)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat error: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("file mode changed: %v", info.Mode())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
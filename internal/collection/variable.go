@@ -0,0 +1,237 @@
+package collection
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Variable is a collection, folder or URL path variable.
+// Postman allows non-string values; they are kept as their JSON text.
+type Variable struct {
+	ID          string      `json:"id,omitempty"`
+	Key         string      `json:"key"`
+	Value       string      `json:"value"`
+	Type        string      `json:"type,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	Description Description `json:"description,omitzero"`
+	System      bool        `json:"system,omitempty"`
+	Disabled    bool        `json:"disabled,omitempty"`
+
+	// rawValue is the original non-string value, written back while Value is unchanged
+	rawValue json.RawMessage
+	extra    map[string]json.RawMessage
+}
+
+// variableJSON is the wire form of Variable with the value kept as JSON
+type variableJSON struct {
+	ID          string          `json:"id,omitempty"`
+	Key         string          `json:"key"`
+	Value       json.RawMessage `json:"value"`
+	Type        string          `json:"type,omitempty"`
+	Name        string          `json:"name,omitempty"`
+	Description Description     `json:"description,omitzero"`
+	System      bool            `json:"system,omitempty"`
+	Disabled    bool            `json:"disabled,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (v *Variable) UnmarshalJSON(data []byte) error {
+	var raw variableJSON
+	extra, err := decodeObject(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	*v = Variable{
+		ID:          raw.ID,
+		Key:         raw.Key,
+		Type:        raw.Type,
+		Name:        raw.Name,
+		Description: raw.Description,
+		System:      raw.System,
+		Disabled:    raw.Disabled,
+		extra:       extra,
+	}
+	switch value := bytes.TrimSpace(raw.Value); {
+	case len(value) == 0, bytes.Equal(value, []byte("null")):
+	case isJSONString(value):
+		return json.Unmarshal(value, &v.Value)
+	default:
+		v.Value = string(value)
+		v.rawValue = value
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (v Variable) MarshalJSON() ([]byte, error) {
+	value := v.rawValue
+	if value == nil || v.Value != string(value) {
+		var err error
+		if value, err = marshal(v.Value); err != nil {
+			return nil, err
+		}
+	}
+	return encodeObject(&variableJSON{
+		ID:          v.ID,
+		Key:         v.Key,
+		Value:       value,
+		Type:        v.Type,
+		Name:        v.Name,
+		Description: v.Description,
+		System:      v.System,
+		Disabled:    v.Disabled,
+	}, v.extra)
+}
+
+// VariableMap returns the enabled variables as a map from key to value
+func VariableMap(vars []Variable) map[string]string {
+	m := make(map[string]string, len(vars))
+	for _, v := range vars {
+		if !v.Disabled {
+			m[v.Key] = v.Value
+		}
+	}
+	return m
+}
+
+// MergeVariables merges variable scopes given from the lowest to the highest
+// precedence, e.g. collection variables followed by environment variables,
+// so a key defined in a later scope overrides the earlier ones.
+func MergeVariables(scopes ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, scope := range scopes {
+		for k, v := range scope {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// ErrVariableCycle is returned when variables reference each other in a cycle
+var ErrVariableCycle = errors.New("variable reference cycle")
+
+// maxResolveRounds bounds the substitution rounds of a single text, so
+// placeholders assembled from other placeholders cannot grow forever
+const maxResolveRounds = 64
+
+// SubstituteVariables replaces {{var}} in a string with values from vars,
+// resolving variables that reference other variables. Placeholders that are
+// undefined or part of a cycle are left as they are.
+func SubstituteVariables(s string, vars map[string]string) string {
+	resolved, _, _ := ResolveVariables(s, vars)
+	return resolved
+}
+
+// ResolveVariables replaces the {{var}} placeholders in s with values from
+// vars until none of the defined ones is left. Values may reference other
+// variables and placeholders may be built from others, e.g. {{url_{{env}}}}.
+// It returns the resolved text, the undefined variable names in order of
+// appearance and an ErrVariableCycle error naming the cycle, if any. Dynamic
+// variables are neither resolved nor reported as undefined.
+func ResolveVariables(s string, vars map[string]string) (string, []string, error) {
+	r := &resolver{
+		vars:     vars,
+		resolved: make(map[string]string),
+		active:   make(map[string]bool),
+		cyclic:   make(map[string]bool),
+		seen:     make(map[string]bool),
+	}
+	return r.resolve(s), r.undefined, r.err
+}
+
+// resolver resolves placeholders depth first, tracking the variables being
+// resolved to detect cycles
+type resolver struct {
+	vars      map[string]string
+	resolved  map[string]string
+	stack     []string
+	active    map[string]bool
+	cyclic    map[string]bool
+	undefined []string
+	seen      map[string]bool
+	err       error
+}
+
+func (r *resolver) resolve(s string) string {
+	for range maxResolveRounds {
+		changed := false
+		s = placeholderRe.ReplaceAllStringFunc(s, func(placeholder string) string {
+			value, ok := r.lookup(placeholder[2 : len(placeholder)-2])
+			if !ok {
+				return placeholder
+			}
+			changed = true
+			return value
+		})
+		if !changed {
+			return s
+		}
+	}
+	if r.err == nil {
+		r.err = fmt.Errorf("%w in %q", ErrVariableCycle, s)
+	}
+	return s
+}
+
+// lookup returns the resolved value of the variable name
+func (r *resolver) lookup(name string) (string, bool) {
+	if value, ok := r.resolved[name]; ok {
+		return value, true
+	}
+	value, ok := r.vars[name]
+	if !ok {
+		if !r.seen[name] && !IsDynamicVariable(name) {
+			r.seen[name] = true
+			r.undefined = append(r.undefined, name)
+		}
+		return "", false
+	}
+	if r.cyclic[name] {
+		return "", false
+	}
+	if r.active[name] {
+		// the variables of a cycle stay unresolved
+		cycle := slices.Concat(r.stack[slices.Index(r.stack, name):], []string{name})
+		for _, n := range cycle {
+			r.cyclic[n] = true
+		}
+		if r.err == nil {
+			r.err = fmt.Errorf("%w: %s", ErrVariableCycle, strings.Join(cycle, " -> "))
+		}
+		return "", false
+	}
+
+	r.active[name] = true
+	r.stack = append(r.stack, name)
+	value = r.resolve(value)
+	r.stack = r.stack[:len(r.stack)-1]
+	r.active[name] = false
+
+	if r.cyclic[name] {
+		return "", false
+	}
+	r.resolved[name] = value
+	return value, true
+}
+
+// placeholderRe matches a {{var}} placeholder
+var placeholderRe = regexp.MustCompile(`\{\{([^{}]+)\}\}`)
+
+// Placeholders returns the start and end index of every {{var}} placeholder in s
+func Placeholders(s string) [][]int {
+	return placeholderRe.FindAllStringIndex(s, -1)
+}
+
+// UnresolvedVariables returns the names of the {{var}} placeholders in s,
+// including those referenced by variable values, that neither vars nor the
+// dynamic variables define, each name once, in order of appearance
+func UnresolvedVariables(s string, vars map[string]string) []string {
+	_, undefined, _ := ResolveVariables(s, vars)
+	return undefined
+}
@@ -0,0 +1,79 @@
+package environment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Environment represents a Postman environment file (*.postman_environment.json)
+type Environment struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Values []Value `json:"values"`
+}
+
+// Value is a single environment variable. Postman omits "enabled" in some
+// exports, in which case the variable is enabled. Non-string values, e.g.
+// numbers and booleans, are kept as their JSON text.
+type Value struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Type    string `json:"type"`
+	Enabled *bool  `json:"enabled"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (v *Value) UnmarshalJSON(data []byte) error {
+	// value is the variable without its methods, with the value as JSON
+	type value Value
+	var raw struct {
+		value
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*v = Value(raw.value)
+	switch text := bytes.TrimSpace(raw.Value); {
+	case len(text) == 0, bytes.Equal(text, []byte("null")):
+	case text[0] == '"':
+		return json.Unmarshal(text, &v.Value)
+	default:
+		v.Value = string(text)
+	}
+	return nil
+}
+
+// IsEnabled reports whether the variable is in effect
+func (v Value) IsEnabled() bool {
+	return v.Enabled == nil || *v.Enabled
+}
+
+// VariableMap returns the enabled variables as a map from key to value
+func (e *Environment) VariableMap() map[string]string {
+	m := make(map[string]string, len(e.Values))
+	for _, v := range e.Values {
+		if v.IsEnabled() {
+			m[v.Key] = v.Value
+		}
+	}
+	return m
+}
+
+// Load loads and parses the Postman environment from file
+func Load(path string) (*Environment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Postman environment: %v", err)
+	}
+	var env Environment
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("error parsing Postman environment: %v", err)
+	}
+	if env.Name == "" {
+		return nil, fmt.Errorf("error parsing Postman environment: %s has no name", path)
+	}
+	return &env, nil
+}
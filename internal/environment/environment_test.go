@@ -0,0 +1,61 @@
+package environment
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	jsonData := `{
+		"id": "5f2c",
+		"name": "Staging",
+		"values": [
+			{"key": "base_url", "value": "https://staging.example.com", "type": "default", "enabled": true},
+			{"key": "token", "value": "secret", "type": "secret"},
+			{"key": "debug", "value": "true", "enabled": false},
+			{"key": "port", "value": 8080},
+			{"key": "retry", "value": false, "enabled": true},
+			{"key": "empty", "value": null}
+		],
+		"_postman_variable_scope": "environment"
+	}`
+	path := filepath.Join(t.TempDir(), "staging.postman_environment.json")
+	if err := os.WriteFile(path, []byte(jsonData), 0o644); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	env, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if env.Name != "Staging" || env.ID != "5f2c" || len(env.Values) != 6 {
+		t.Errorf("unexpected environment: %+v", env)
+	}
+
+	want := map[string]string{"base_url": "https://staging.example.com", "token": "secret", "port": "8080", "retry": "false", "empty": ""}
+	if got := env.VariableMap(); !reflect.DeepEqual(got, want) {
+		t.Errorf("VariableMap() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Load(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+
+	for name, content := range map[string]string{
+		"invalid.json": `{invalid json}`,
+		"noname.json":  `{"values": []}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("expected error for %s", name)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Authenticator adds credentials to a request before it is sent
+type Authenticator interface {
+	Authenticate(rq *http.Request) error
+}
+
+// RetryAuthenticator is an Authenticator that can answer a rejection of the
+// server, e.g. by renewing its credentials
+type RetryAuthenticator interface {
+	Authenticator
+	// Reauthenticate updates rq, a copy of the request that got resp, and
+	// reports whether it should be sent again
+	Reauthenticate(rq *http.Request, resp *http.Response) (bool, error)
+}
+
+// authStateKey is the request context key of the request's authState
+type authStateKey struct{}
+
+// authState keeps the authenticator of a request for SendRequest
+type authState struct {
+	auth       Authenticator
+	challenged bool
+}
+
+// BasicAuth authenticates with a username and password
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Authenticate implements Authenticator
+func (a BasicAuth) Authenticate(rq *http.Request) error {
+	rq.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+// BearerAuth authenticates with a bearer token
+type BearerAuth struct {
+	Token string
+}
+
+// Authenticate implements Authenticator
+func (a BearerAuth) Authenticate(rq *http.Request) error {
+	rq.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+// APIKeyAuth authenticates with a key and value sent as a header or, when
+// InQuery is set, as a query parameter
+type APIKeyAuth struct {
+	Key     string
+	Value   string
+	InQuery bool
+}
+
+// Authenticate implements Authenticator
+func (a APIKeyAuth) Authenticate(rq *http.Request) error {
+	if !a.InQuery {
+		rq.Header.Set(a.Key, a.Value)
+		return nil
+	}
+	param := url.QueryEscape(a.Key) + "=" + url.QueryEscape(a.Value)
+	if rq.URL.RawQuery == "" {
+		rq.URL.RawQuery = param
+	} else {
+		rq.URL.RawQuery += "&" + param
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	awsV4Algorithm   = "AWS4-HMAC-SHA256"
+	awsV4TimeFormat  = "20060102T150405Z"
+	awsV4DateFormat  = "20060102"
+	awsV4EmptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// awsV4UnsignedHeaders are headers left out of the signature as they may be
+// changed on the way
+var awsV4UnsignedHeaders = map[string]bool{
+	"authorization":   true,
+	"user-agent":      true,
+	"expect":          true,
+	"x-amzn-trace-id": true,
+}
+
+// AWSV4Auth signs requests with AWS Signature Version 4
+type AWSV4Auth struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	Region       string
+	Service      string
+
+	// now returns the signing time, time.Now when nil
+	now func() time.Time
+}
+
+// Authenticate implements Authenticator. It signs the request as it is, so
+// it must be the last change to the request.
+func (a AWSV4Auth) Authenticate(rq *http.Request) error {
+	now := time.Now
+	if a.now != nil {
+		now = a.now
+	}
+	t := now().UTC()
+
+	payloadHash, err := payloadSHA256(rq)
+	if err != nil {
+		return fmt.Errorf("error signing request: %v", err)
+	}
+
+	rq.Header.Set("X-Amz-Date", t.Format(awsV4TimeFormat))
+	if a.SessionToken != "" {
+		rq.Header.Set("X-Amz-Security-Token", a.SessionToken)
+	}
+	if a.Service == "s3" {
+		rq.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	signedHeaders, canonicalHeaders := awsV4Headers(rq)
+	canonicalRequest := strings.Join([]string{
+		rq.Method,
+		awsV4URI(rq.URL, a.Service != "s3"),
+		awsV4Query(rq.URL),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{t.Format(awsV4DateFormat), a.Region, a.Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		awsV4Algorithm,
+		t.Format(awsV4TimeFormat),
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+a.SecretKey), t.Format(awsV4DateFormat))
+	for _, part := range []string{a.Region, a.Service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	rq.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsV4Algorithm, a.AccessKey, scope, signedHeaders, signature))
+	return nil
+}
+
+// payloadSHA256 returns the hex SHA-256 of the request body, leaving the
+// body to be sent
+func payloadSHA256(rq *http.Request) (string, error) {
+	if rq.Body == nil || rq.Body == http.NoBody {
+		return awsV4EmptySHA256, nil
+	}
+	if rq.GetBody == nil {
+		body, err := io.ReadAll(rq.Body)
+		if err != nil {
+			return "", err
+		}
+		rq.Body.Close()
+		rq.Body = io.NopCloser(bytes.NewReader(body))
+		return hexSHA256(body), nil
+	}
+	body, err := rq.GetBody()
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// awsV4Headers returns the signed header names and the canonical headers
+func awsV4Headers(rq *http.Request) (string, string) {
+	values := map[string][]string{}
+	for name, vs := range rq.Header {
+		name = strings.ToLower(name)
+		if awsV4UnsignedHeaders[name] {
+			continue
+		}
+		for _, v := range vs {
+			values[name] = append(values[name], strings.Join(strings.Fields(v), " "))
+		}
+	}
+	host := rq.Host
+	if host == "" {
+		host = rq.URL.Host
+	}
+	values["host"] = []string{host}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + strings.Join(values[name], ",") + "\n")
+	}
+	return strings.Join(names, ";"), canonical.String()
+}
+
+// awsV4URI returns the canonical URI of u. Services other than S3 normalize
+// the path and encode its already encoded segments once more.
+func awsV4URI(u *url.URL, normalize bool) string {
+	p := u.EscapedPath()
+	if !normalize {
+		p = u.Path
+	}
+	if p == "" {
+		return "/"
+	}
+	if normalize {
+		trailing := strings.HasSuffix(p, "/") && p != "/"
+		p = path.Clean("/" + p)
+		if trailing {
+			p += "/"
+		}
+	}
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = awsV4Escape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// awsV4Query returns the canonical query string of u
+func awsV4Query(u *url.URL) string {
+	if u.RawQuery == "" {
+		return ""
+	}
+	var params [][2]string
+	for _, pair := range strings.Split(u.RawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		if v, err := url.QueryUnescape(value); err == nil {
+			value = v
+		}
+		params = append(params, [2]string{awsV4Escape(key), awsV4Escape(value)})
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i][0] != params[j][0] {
+			return params[i][0] < params[j][0]
+		}
+		return params[i][1] < params[j][1]
+	})
+
+	encoded := make([]string, len(params))
+	for i, p := range params {
+		encoded[i] = p[0] + "=" + p[1]
+	}
+	return strings.Join(encoded, "&")
+}
+
+// awsV4Escape percent-encodes everything but the unreserved characters of RFC 3986
+func awsV4Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,87 @@
+package httpclient
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test vectors of the AWS Signature Version 4 test suite
+func TestAWSV4Auth(t *testing.T) {
+	auth := AWSV4Auth{
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:    "us-east-1",
+		Service:   "service",
+		now:       func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) },
+	}
+	const credential = "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "
+
+	tests := []struct {
+		name    string
+		method  string
+		url     string
+		body    string
+		headers string
+		want    string
+	}{
+		{
+			name:   "get-vanilla",
+			method: "GET",
+			url:    "https://example.amazonaws.com/",
+			want:   "SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name:   "get-vanilla-query-order-key-case",
+			method: "GET",
+			url:    "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			want:   "SignedHeaders=host;x-amz-date, Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+		{
+			name:   "post-vanilla",
+			method: "POST",
+			url:    "https://example.amazonaws.com/",
+			want:   "SignedHeaders=host;x-amz-date, Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+		{
+			name:    "post-x-www-form-urlencoded",
+			method:  "POST",
+			url:     "https://example.amazonaws.com/",
+			body:    "Param1=value1",
+			headers: "Content-Type: application/x-www-form-urlencoded",
+			want:    "SignedHeaders=content-type;host;x-amz-date, Signature=ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rq, err := NewRequest(context.Background(), tt.method, tt.url, tt.body, tt.headers, auth)
+			if err != nil {
+				t.Fatalf("NewRequest error: %v", err)
+			}
+			if got := rq.Header.Get("Authorization"); got != credential+tt.want {
+				t.Errorf("Authorization = %q, want %q", got, credential+tt.want)
+			}
+			if got := rq.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+				t.Errorf("X-Amz-Date = %q", got)
+			}
+		})
+	}
+}
+
+func TestAWSV4AuthSessionToken(t *testing.T) {
+	auth := AWSV4Auth{AccessKey: "AKID", SecretKey: "secret", SessionToken: "token", Region: "eu-west-1", Service: "s3"}
+	rq, err := NewRequest(context.Background(), "PUT", "https://bucket.s3.amazonaws.com/my%20key", "data", "", auth)
+	if err != nil {
+		t.Fatalf("NewRequest error: %v", err)
+	}
+	if rq.Header.Get("X-Amz-Security-Token") != "token" {
+		t.Errorf("X-Amz-Security-Token = %q", rq.Header.Get("X-Amz-Security-Token"))
+	}
+	if got, want := rq.Header.Get("X-Amz-Content-Sha256"), hexSHA256([]byte("data")); got != want {
+		t.Errorf("X-Amz-Content-Sha256 = %q, want %q", got, want)
+	}
+	if got := rq.Header.Get("Authorization"); !strings.Contains(got, "SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token,") {
+		t.Errorf("Authorization = %q", got)
+	}
+}
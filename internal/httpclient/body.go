@@ -0,0 +1,141 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Body is an encoded request body and its content type
+type Body struct {
+	Data        []byte
+	ContentType string
+}
+
+// FormField is a field of a urlencoded or multipart body. A multipart field
+// with File set sends the content of that file.
+type FormField struct {
+	Key         string
+	Value       string
+	File        string
+	ContentType string
+}
+
+// rawContentTypes maps the Postman raw body languages to content types
+var rawContentTypes = map[string]string{
+	"json":       "application/json",
+	"xml":        "application/xml",
+	"html":       "text/html",
+	"javascript": "application/javascript",
+	"text":       "text/plain",
+}
+
+// RawBody returns a raw body in language, e.g. "json" or "xml". The content
+// type is left empty for an unknown language.
+func RawBody(raw, language string) Body {
+	return Body{Data: []byte(raw), ContentType: rawContentTypes[language]}
+}
+
+// URLEncodedBody returns an application/x-www-form-urlencoded body of fields
+// in their order
+func URLEncodedBody(fields []FormField) Body {
+	pairs := make([]string, len(fields))
+	for i, f := range fields {
+		pairs[i] = url.QueryEscape(f.Key) + "=" + url.QueryEscape(f.Value)
+	}
+	return Body{Data: []byte(strings.Join(pairs, "&")), ContentType: "application/x-www-form-urlencoded"}
+}
+
+// MultipartBody returns a multipart/form-data body of fields, reading the
+// content of file fields
+func MultipartBody(fields []FormField) (Body, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, f := range fields {
+		if f.File == "" {
+			if f.ContentType == "" {
+				if err := w.WriteField(f.Key, f.Value); err != nil {
+					return Body{}, err
+				}
+				continue
+			}
+			h := make(textproto.MIMEHeader)
+			h.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{"name": f.Key}))
+			h.Set("Content-Type", f.ContentType)
+			part, err := w.CreatePart(h)
+			if err != nil {
+				return Body{}, err
+			}
+			if _, err := part.Write([]byte(f.Value)); err != nil {
+				return Body{}, err
+			}
+			continue
+		}
+
+		data, err := os.ReadFile(f.File)
+		if err != nil {
+			return Body{}, fmt.Errorf("error reading form file: %v", err)
+		}
+		contentType := f.ContentType
+		if contentType == "" {
+			contentType = fileContentType(f.File)
+		}
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{"name": f.Key, "filename": filepath.Base(f.File)}))
+		h.Set("Content-Type", contentType)
+		part, err := w.CreatePart(h)
+		if err != nil {
+			return Body{}, err
+		}
+		if _, err := part.Write(data); err != nil {
+			return Body{}, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return Body{}, err
+	}
+	return Body{Data: buf.Bytes(), ContentType: w.FormDataContentType()}, nil
+}
+
+// FileBody returns a body with the content of the file at path
+func FileBody(path string) (Body, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Body{}, fmt.Errorf("error reading body file: %v", err)
+	}
+	return Body{Data: data, ContentType: fileContentType(path)}, nil
+}
+
+// GraphQLBody returns a GraphQL request body. variables is a JSON object or empty.
+func GraphQLBody(query, variables string) (Body, error) {
+	payload := struct {
+		Query     string          `json:"query"`
+		Variables json.RawMessage `json:"variables,omitempty"`
+	}{Query: query}
+	if strings.TrimSpace(variables) != "" {
+		if !json.Valid([]byte(variables)) {
+			return Body{}, fmt.Errorf("GraphQL variables are not valid JSON")
+		}
+		payload.Variables = json.RawMessage(variables)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Body{}, err
+	}
+	return Body{Data: data, ContentType: "application/json"}, nil
+}
+
+// fileContentType guesses the content type of a file from its extension
+func fileContentType(path string) string {
+	if t := mime.TypeByExtension(filepath.Ext(path)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
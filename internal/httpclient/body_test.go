@@ -0,0 +1,148 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRawBody(t *testing.T) {
+	if b := RawBody(`{"a":1}`, "json"); string(b.Data) != `{"a":1}` || b.ContentType != "application/json" {
+		t.Errorf("RawBody(json) = %q %q", b.Data, b.ContentType)
+	}
+	if b := RawBody("x", "unknown"); b.ContentType != "" {
+		t.Errorf("RawBody(unknown) content type = %q, want empty", b.ContentType)
+	}
+}
+
+func TestURLEncodedBody(t *testing.T) {
+	b := URLEncodedBody([]FormField{{Key: "q", Value: "a b&c"}, {Key: "ü", Value: "1=2"}, {Key: "q", Value: "d"}})
+	if want := "q=a+b%26c&%C3%BC=1%3D2&q=d"; string(b.Data) != want {
+		t.Errorf("URLEncodedBody() = %q, want %q", b.Data, want)
+	}
+	if b.ContentType != "application/x-www-form-urlencoded" {
+		t.Errorf("URLEncodedBody() content type = %q", b.ContentType)
+	}
+}
+
+func TestMultipartBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	if err := os.WriteFile(path, []byte(`{"file":true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := MultipartBody([]FormField{
+		{Key: "name", Value: "value"},
+		{Key: "meta", Value: "<a/>", ContentType: "application/xml"},
+		{Key: "upload", File: path},
+	})
+	if err != nil {
+		t.Fatalf("MultipartBody() error: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(b.ContentType)
+	if err != nil || mediaType != "multipart/form-data" {
+		t.Fatalf("MultipartBody() content type = %q, %v", b.ContentType, err)
+	}
+
+	r := multipart.NewReader(strings.NewReader(string(b.Data)), params["boundary"])
+	want := []struct{ name, filename, contentType, data string }{
+		{"name", "", "", "value"},
+		{"meta", "", "application/xml", "<a/>"},
+		{"upload", "data.json", "application/json", `{"file":true}`},
+	}
+	for _, w := range want {
+		part, err := r.NextPart()
+		if err != nil {
+			t.Fatalf("NextPart() error: %v", err)
+		}
+		data, _ := io.ReadAll(part)
+		if part.FormName() != w.name || part.FileName() != w.filename || string(data) != w.data {
+			t.Errorf("part = %q %q %q, want %q %q %q", part.FormName(), part.FileName(), data, w.name, w.filename, w.data)
+		}
+		if w.contentType != "" && part.Header.Get("Content-Type") != w.contentType {
+			t.Errorf("part %q content type = %q, want %q", w.name, part.Header.Get("Content-Type"), w.contentType)
+		}
+	}
+	if _, err := r.NextPart(); err != io.EOF {
+		t.Errorf("NextPart() after the last part = %v, want EOF", err)
+	}
+
+	if _, err := MultipartBody([]FormField{{Key: "f", File: filepath.Join(t.TempDir(), "missing")}}); err == nil {
+		t.Errorf("MultipartBody() with a missing file succeeded")
+	}
+}
+
+func TestFileBody(t *testing.T) {
+	dir := t.TempDir()
+	png := filepath.Join(dir, "image.png")
+	if err := os.WriteFile(png, []byte("\x89PNG"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := FileBody(png)
+	if err != nil || string(b.Data) != "\x89PNG" || b.ContentType != "image/png" {
+		t.Errorf("FileBody(png) = %q %q, %v", b.Data, b.ContentType, err)
+	}
+
+	unknown := filepath.Join(dir, "data")
+	if err := os.WriteFile(unknown, []byte{0, 1}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := FileBody(unknown); b.ContentType != "application/octet-stream" {
+		t.Errorf("FileBody() content type = %q, want application/octet-stream", b.ContentType)
+	}
+
+	if _, err := FileBody(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("FileBody() of a missing file succeeded")
+	}
+}
+
+func TestGraphQLBody(t *testing.T) {
+	b, err := GraphQLBody("query($id: ID!) { user(id: $id) { name } }", `{"id": "1"}`)
+	if err != nil {
+		t.Fatalf("GraphQLBody() error: %v", err)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(b.Data, &payload); err != nil {
+		t.Fatalf("GraphQLBody() is not JSON: %v", err)
+	}
+	if payload["query"] != "query($id: ID!) { user(id: $id) { name } }" || payload["variables"].(map[string]any)["id"] != "1" {
+		t.Errorf("GraphQLBody() = %s", b.Data)
+	}
+	if b.ContentType != "application/json" {
+		t.Errorf("GraphQLBody() content type = %q", b.ContentType)
+	}
+
+	if b, err := GraphQLBody("{ me }", " "); err != nil || string(b.Data) != `{"query":"{ me }"}` {
+		t.Errorf("GraphQLBody() without variables = %s, %v", b.Data, err)
+	}
+	if _, err := GraphQLBody("{ me }", "{id: 1}"); err == nil {
+		t.Errorf("GraphQLBody() with invalid variables succeeded")
+	}
+}
+
+func TestNewRequestWithBodyContentType(t *testing.T) {
+	rq, err := NewRequestWithBody(context.Background(), "POST", "http://example.com", Body{Data: []byte("a=1"), ContentType: "application/x-www-form-urlencoded"}, "", nil)
+	if err != nil {
+		t.Fatalf("NewRequestWithBody error: %v", err)
+	}
+	if got := rq.Header.Get("Content-Type"); got != "application/x-www-form-urlencoded" {
+		t.Errorf("Content-Type = %q, want the body content type", got)
+	}
+	if data, _ := io.ReadAll(rq.Body); string(data) != "a=1" {
+		t.Errorf("body = %q, want a=1", data)
+	}
+
+	rq, err = NewRequestWithBody(context.Background(), "POST", "http://example.com", Body{Data: []byte("{}"), ContentType: "application/json"}, "content-type: application/vnd.api+json", nil)
+	if err != nil {
+		t.Fatalf("NewRequestWithBody error: %v", err)
+	}
+	if got := rq.Header.Get("Content-Type"); got != "application/vnd.api+json" {
+		t.Errorf("Content-Type = %q, want the header of the request", got)
+	}
+}
@@ -0,0 +1,146 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// defaultMaxResponseSize is the size of the response body read into memory
+// when Settings do not set one
+const defaultMaxResponseSize = 2 * 1024 * 1024 // 2 MB
+
+// responseDir is the temporary directory of the response body files,
+// created with the first one
+var responseDir struct {
+	sync.Mutex
+	path string
+}
+
+// readBody reads up to limit bytes of body into memory. With keep, a larger
+// body is written whole to a temporary file whose path is returned. size is
+// the size of the whole body, -1 when it was dropped unread.
+func readBody(body io.Reader, limit int64, keep bool) (data []byte, size int64, file string, err error) {
+	if !keep {
+		// one byte more tells whether the body is larger than limit
+		data, err = io.ReadAll(io.LimitReader(body, limit+1))
+		if err != nil {
+			return nil, 0, "", err
+		}
+		if int64(len(data)) > limit {
+			return data[:limit], -1, "", nil
+		}
+		return data, int64(len(data)), "", nil
+	}
+
+	data, err = io.ReadAll(io.LimitReader(body, limit))
+	if err != nil {
+		return nil, 0, "", err
+	}
+	// the first byte after data tells whether there is more
+	var next [1]byte
+	n, err := io.ReadFull(body, next[:])
+	if n == 0 {
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, 0, "", err
+		}
+		return data, int64(len(data)), "", nil
+	}
+
+	f, err := createBodyFile()
+	if err != nil {
+		return nil, 0, "", err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(f.Name())
+			file = ""
+		}
+	}()
+	if _, err = f.Write(data); err != nil {
+		return nil, 0, "", err
+	}
+	if _, err = f.Write(next[:]); err != nil {
+		return nil, 0, "", err
+	}
+	rest, err := io.Copy(f, body)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	return data, int64(len(data)) + 1 + rest, f.Name(), nil
+}
+
+// createBodyFile creates a temporary file for a response body
+func createBodyFile() (*os.File, error) {
+	responseDir.Lock()
+	defer responseDir.Unlock()
+	if responseDir.path == "" {
+		dir, err := os.MkdirTemp("", "ghostman-responses-")
+		if err != nil {
+			return nil, fmt.Errorf("error creating the response directory: %w", err)
+		}
+		responseDir.path = dir
+	}
+	return os.CreateTemp(responseDir.path, "body-*")
+}
+
+// RemoveResponseFiles removes the temporary files of all response bodies,
+// e.g. when the application exits
+func RemoveResponseFiles() error {
+	responseDir.Lock()
+	defer responseDir.Unlock()
+	if responseDir.path == "" {
+		return nil
+	}
+	err := os.RemoveAll(responseDir.path)
+	responseDir.path = ""
+	return err
+}
+
+// Truncated reports whether the body holds only the start of a larger body
+func (r *Response) Truncated() bool {
+	return r.FullSize < 0 || r.FullSize > int64(len(r.Body))
+}
+
+// Chunks returns the count of chunks of the whole body in BodyFile, each as
+// large as Body, the first chunk
+func (r *Response) Chunks() int {
+	if r.BodyFile == "" || len(r.Body) == 0 {
+		return 1
+	}
+	size := int64(len(r.Body))
+	return int((r.FullSize + size - 1) / size)
+}
+
+// Chunk returns the chunk i of the whole body, as text like Text
+func (r *Response) Chunk(i int) (string, error) {
+	if i == 0 || r.BodyFile == "" {
+		return r.Text(), nil
+	}
+	f, err := os.Open(r.BodyFile)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	data := make([]byte, len(r.Body))
+	n, err := f.ReadAt(data, int64(i)*int64(len(r.Body)))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return text(data[:n]), nil
+}
+
+// RemoveBodyFile removes the temporary file of the whole body, if any
+func (r *Response) RemoveBodyFile() error {
+	if r.BodyFile == "" {
+		return nil
+	}
+	err := os.Remove(r.BodyFile)
+	r.BodyFile = ""
+	return err
+}
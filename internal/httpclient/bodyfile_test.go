@@ -0,0 +1,64 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSendLimit(t *testing.T) {
+	body := strings.Repeat("0123456789", 10)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+		io.WriteString(w, body[:size])
+	}))
+	defer ts.Close()
+	defer RemoveResponseFiles()
+	s := Settings{MaxResponseSize: 40}
+
+	send := func(size int) *Response {
+		t.Helper()
+		resp, err := sendWith(t, s, ts.URL+"?size="+strconv.Itoa(size))
+		if err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+		return resp
+	}
+
+	resp := send(100)
+	if !resp.Truncated() || resp.FullSize != -1 || string(resp.Body) != body[:40] || resp.BodyFile != "" {
+		t.Errorf("unexpected truncated response: %d %q %q", resp.FullSize, resp.Body, resp.BodyFile)
+	}
+	if resp := send(40); resp.Truncated() || resp.FullSize != 40 {
+		t.Errorf("a body of the limit size is truncated: %d", resp.FullSize)
+	}
+
+	s.KeepFullResponse = true
+	if resp := send(40); resp.Truncated() || resp.BodyFile != "" {
+		t.Errorf("a body of the limit size is kept in %q", resp.BodyFile)
+	}
+	resp = send(100)
+	if !resp.Truncated() || resp.FullSize != 100 || string(resp.Body) != body[:40] || resp.Chunks() != 3 {
+		t.Fatalf("unexpected kept response: %d %q, %d chunks", resp.FullSize, resp.Body, resp.Chunks())
+	}
+	if data, err := os.ReadFile(resp.BodyFile); err != nil || string(data) != body {
+		t.Errorf("body file = %q, %v, want the whole body", data, err)
+	}
+	for i, want := range []string{body[:40], body[40:80], body[80:]} {
+		if got, err := resp.Chunk(i); err != nil || got != want {
+			t.Errorf("Chunk(%d) = %q, %v, want %q", i, got, err, want)
+		}
+	}
+
+	file := resp.BodyFile
+	if err := resp.RemoveBodyFile(); err != nil || resp.BodyFile != "" {
+		t.Errorf("RemoveBodyFile error: %v", err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("body file %q still exists", file)
+	}
+}
@@ -0,0 +1,235 @@
+package httpclient
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// DigestAuth authenticates with HTTP Digest access authentication (RFC 7616).
+// The request is sent without credentials first and answered once the
+// server sends its challenge.
+type DigestAuth struct {
+	Username string
+	Password string
+}
+
+// Authenticate implements Authenticator. Digest needs the challenge of the
+// server, the credentials are added by Reauthenticate.
+func (a DigestAuth) Authenticate(rq *http.Request) error {
+	return nil
+}
+
+// Reauthenticate implements RetryAuthenticator by answering a Digest challenge
+func (a DigestAuth) Reauthenticate(rq *http.Request, resp *http.Response) (bool, error) {
+	if resp.StatusCode != http.StatusUnauthorized {
+		return false, nil
+	}
+	challenge, ok := digestChallenge(resp.Header.Values("WWW-Authenticate"))
+	if !ok {
+		return false, nil
+	}
+	authorization, err := a.authorization(rq, challenge)
+	if err != nil {
+		return false, err
+	}
+	rq.Header.Set("Authorization", authorization)
+	return true, nil
+}
+
+// authorization returns the Authorization header answering challenge
+func (a DigestAuth) authorization(rq *http.Request, challenge map[string]string) (string, error) {
+	algorithm := challenge["algorithm"]
+	if algorithm == "" {
+		algorithm = "MD5"
+	}
+	newHash := digestHash(algorithm)
+	if newHash == nil {
+		return "", fmt.Errorf("unsupported digest algorithm %q", algorithm)
+	}
+	h := func(parts ...string) string {
+		d := newHash()
+		io.WriteString(d, strings.Join(parts, ":"))
+		return hex.EncodeToString(d.Sum(nil))
+	}
+
+	var qop string
+	offered := strings.Split(challenge["qop"], ",")
+	for i := range offered {
+		offered[i] = strings.TrimSpace(offered[i])
+	}
+	switch {
+	case slices.Contains(offered, "auth"):
+		qop = "auth"
+	case slices.Contains(offered, "auth-int"):
+		qop = "auth-int"
+	case challenge["qop"] != "":
+		return "", fmt.Errorf("unsupported digest qop %q", challenge["qop"])
+	}
+
+	cnonceBytes := make([]byte, 16)
+	if _, err := rand.Read(cnonceBytes); err != nil {
+		return "", err
+	}
+	cnonce := hex.EncodeToString(cnonceBytes)
+	const nc = "00000001"
+	realm, nonce := challenge["realm"], challenge["nonce"]
+	uri := rq.URL.RequestURI()
+
+	ha1 := h(a.Username, realm, a.Password)
+	if strings.HasSuffix(strings.ToUpper(algorithm), "-SESS") {
+		ha1 = h(ha1, nonce, cnonce)
+	}
+	ha2 := h(rq.Method, uri)
+	if qop == "auth-int" {
+		body, err := requestBody(rq)
+		if err != nil {
+			return "", err
+		}
+		ha2 = h(rq.Method, uri, h(string(body)))
+	}
+
+	var response string
+	if qop == "" {
+		response = h(ha1, nonce, ha2)
+	} else {
+		response = h(ha1, nonce, nc, cnonce, qop, ha2)
+	}
+
+	params := []string{
+		"username=" + quote(a.Username),
+		"realm=" + quote(realm),
+		"nonce=" + quote(nonce),
+		"uri=" + quote(uri),
+		"algorithm=" + algorithm,
+		"response=" + quote(response),
+	}
+	if qop != "" {
+		params = append(params, "qop="+qop, "nc="+nc, "cnonce="+quote(cnonce))
+	}
+	if opaque, ok := challenge["opaque"]; ok {
+		params = append(params, "opaque="+quote(opaque))
+	}
+	return "Digest " + strings.Join(params, ", "), nil
+}
+
+// quote returns s as a quoted string of RFC 7230
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// requestBody returns a copy of the body of rq, leaving the body to be sent
+func requestBody(rq *http.Request) ([]byte, error) {
+	if rq.GetBody == nil {
+		return nil, nil
+	}
+	body, err := rq.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// digestHash returns the hash of a Digest algorithm, nil for an unsupported
+// one. An empty algorithm is MD5.
+func digestHash(algorithm string) func() hash.Hash {
+	switch strings.TrimSuffix(strings.ToUpper(algorithm), "-SESS") {
+	case "MD5", "":
+		return md5.New
+	case "SHA-256":
+		return sha256.New
+	}
+	return nil
+}
+
+// digestChallenge returns the parameters of the strongest Digest challenge
+// with a supported algorithm among the WWW-Authenticate header values
+func digestChallenge(headers []string) (map[string]string, bool) {
+	var best map[string]string
+	isSHA256 := func(params map[string]string) bool {
+		return strings.HasPrefix(strings.ToUpper(params["algorithm"]), "SHA-256")
+	}
+	for _, header := range headers {
+		for _, challenge := range splitChallenges(header) {
+			scheme, rest, _ := strings.Cut(challenge, " ")
+			if !strings.EqualFold(scheme, "Digest") {
+				continue
+			}
+			params := parseAuthParams(rest)
+			if digestHash(params["algorithm"]) == nil {
+				continue
+			}
+			if best == nil || isSHA256(params) && !isSHA256(best) {
+				best = params
+			}
+		}
+	}
+	return best, best != nil
+}
+
+// splitChallenges splits a WWW-Authenticate value holding several challenges.
+// A new challenge starts with a scheme token not followed by "=".
+func splitChallenges(header string) []string {
+	var challenges []string
+	start := 0
+	for _, part := range splitQuoted(header, ',') {
+		token := strings.TrimSpace(header[part[0]:part[1]])
+		name, _, _ := strings.Cut(token, " ")
+		if part[0] > 0 && !strings.Contains(name, "=") && token != "" {
+			challenges = append(challenges, strings.TrimSpace(header[start:part[0]-1]))
+			start = part[0]
+		}
+	}
+	return append(challenges, strings.TrimSpace(header[start:]))
+}
+
+// parseAuthParams parses comma separated auth parameters, unquoting quoted values
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for _, part := range splitQuoted(s, ',') {
+		key, value, ok := strings.Cut(strings.TrimSpace(s[part[0]:part[1]]), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			var b strings.Builder
+			for i := 1; i < len(value)-1; i++ {
+				if value[i] == '\\' && i+1 < len(value)-1 {
+					i++
+				}
+				b.WriteByte(value[i])
+			}
+			value = b.String()
+		}
+		params[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	return params
+}
+
+// splitQuoted returns the start and end offsets of the parts of s separated
+// by sep outside of quoted strings
+func splitQuoted(s string, sep byte) [][2]int {
+	var parts [][2]int
+	start, quoted := 0, false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quoted:
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == sep && !quoted:
+			parts = append(parts, [2]int{start, i})
+			start = i + 1
+		}
+	}
+	return append(parts, [2]int{start, len(s)})
+}
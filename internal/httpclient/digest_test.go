@@ -0,0 +1,127 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newDigestServer returns a server accepting user:pass with Digest auth
+// offering algorithm and qop
+func newDigestServer(t *testing.T, algorithm, qop string) *httptest.Server {
+	const realm, nonce, opaque = "test@example.com", "dcd98b7102dd2f0e8b11d0f600bfb0c093", "5ccc069c403ebaf9f0171e9517f40e41"
+	newHash := md5.New
+	if strings.HasPrefix(algorithm, "SHA-256") {
+		newHash = sha256.New
+	}
+	h := func(parts ...string) string {
+		d := newHash()
+		io.WriteString(d, strings.Join(parts, ":"))
+		return hex.EncodeToString(d.Sum(nil))
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		scheme, rest, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if scheme != "Digest" {
+			w.Header().Add("WWW-Authenticate", `Basic realm="other"`)
+			w.Header().Add("WWW-Authenticate", `Digest realm="`+realm+`", qop="`+qop+`", algorithm=`+algorithm+`, nonce="`+nonce+`", opaque="`+opaque+`"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		p := parseAuthParams(rest)
+		ha1 := h("user", realm, "pass")
+		if strings.HasSuffix(algorithm, "-sess") {
+			ha1 = h(ha1, nonce, p["cnonce"])
+		}
+		ha2 := h(r.Method, r.URL.RequestURI())
+		if p["qop"] == "auth-int" {
+			ha2 = h(r.Method, r.URL.RequestURI(), h(string(body)))
+		}
+		want := h(ha1, nonce, p["nc"], p["cnonce"], p["qop"], ha2)
+		if p["response"] != want || p["uri"] != r.URL.RequestURI() || p["opaque"] != opaque || p["algorithm"] != algorithm {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, `{"authenticated":true,"body":"`+string(body)+`"}`)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestDigestAuth(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		qop       string
+	}{
+		{"MD5", "auth"},
+		{"MD5-sess", "auth"},
+		{"SHA-256", "auth"},
+		{"SHA-256", "auth-int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.algorithm+" "+tt.qop, func(t *testing.T) {
+			ts := newDigestServer(t, tt.algorithm, tt.qop)
+			rq, err := NewRequest(context.Background(), "POST", ts.URL+"/dir/index.html?a=1", "payload", "", DigestAuth{Username: "user", Password: "pass"})
+			if err != nil {
+				t.Fatalf("NewRequest error: %v", err)
+			}
+			status, body, isErr, err := SendRequest(rq)
+			if err != nil || isErr {
+				t.Fatalf("SendRequest = %q %q, %v", status, body, err)
+			}
+			if !strings.Contains(body, "authenticated") || !strings.Contains(body, "payload") {
+				t.Errorf("unexpected body: %s", body)
+			}
+			if !Challenged(rq) {
+				t.Errorf("Challenged() = false, want true")
+			}
+		})
+	}
+}
+
+func TestDigestAuthWrongPassword(t *testing.T) {
+	ts := newDigestServer(t, "MD5", "auth")
+	rq, err := NewRequest(context.Background(), "GET", ts.URL, "", "", DigestAuth{Username: "user", Password: "wrong"})
+	if err != nil {
+		t.Fatalf("NewRequest error: %v", err)
+	}
+	status, _, isErr, err := SendRequest(rq)
+	if err != nil || !isErr || !strings.Contains(status, "401") {
+		t.Errorf("SendRequest status = %q, %v, want 401", status, err)
+	}
+}
+
+func TestDigestChallenge(t *testing.T) {
+	params, ok := digestChallenge([]string{`Basic realm="a, b", Digest realm="x", nonce="n\"1", qop="auth,auth-int", algorithm=MD5, Digest realm="y", nonce="n2", algorithm=SHA-256`})
+	if !ok {
+		t.Fatalf("digestChallenge() found no challenge")
+	}
+	if params["realm"] != "y" || params["nonce"] != "n2" || params["algorithm"] != "SHA-256" {
+		t.Errorf("digestChallenge() = %v, want the SHA-256 challenge", params)
+	}
+
+	// unsupported algorithms are skipped for a supported one
+	params, ok = digestChallenge([]string{`Digest realm="x", nonce="n1", algorithm=SHA-512-256, Digest realm="y", nonce="n2", algorithm=MD5`})
+	if !ok || params["nonce"] != "n2" || params["algorithm"] != "MD5" {
+		t.Errorf("digestChallenge() = %v, %v, want the MD5 challenge", params, ok)
+	}
+	params, ok = digestChallenge([]string{`Digest realm="x", nonce="n1", algorithm=SHA-256-sess`, `Digest realm="y", nonce="n2", algorithm=SHA-256`})
+	if !ok || params["nonce"] != "n1" {
+		t.Errorf("digestChallenge() = %v, %v, want the first SHA-256 challenge", params, ok)
+	}
+	if _, ok := digestChallenge([]string{`Digest realm="x", nonce="n1", algorithm=SHA-512`}); ok {
+		t.Errorf("digestChallenge() returned an unsupported challenge")
+	}
+
+	if _, ok := digestChallenge([]string{`Bearer realm="api"`}); ok {
+		t.Errorf("digestChallenge() found a challenge in a Bearer header")
+	}
+}
@@ -0,0 +1,284 @@
+package httpclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Filename returns the name to save the body under: the filename of the
+// Content-Disposition header, otherwise the last segment of the URL path
+// or "response", with an extension of the content type when it has none
+func (r *Response) Filename() string {
+	if _, params, err := mime.ParseMediaType(r.Header.Get("Content-Disposition")); err == nil {
+		// only the base name, the server does not choose the directory
+		name := filepath.Base(filepath.FromSlash(params["filename"]))
+		if name != "." && name != ".." && name != string(filepath.Separator) {
+			return name
+		}
+	}
+	name := "response"
+	if r.Request != nil {
+		if base := path.Base(r.Request.URL.Path); base != "." && base != "/" {
+			name = base
+		}
+	}
+	if path.Ext(name) == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+			// the shortest one is the common one, e.g. .json or .html
+			name += slices.MinFunc(exts, func(a, b string) int { return len(a) - len(b) })
+		}
+	}
+	return name
+}
+
+// HeaderText returns the headers as "Name: value" lines, sorted by name
+func (r *Response) HeaderText() string {
+	var b strings.Builder
+	r.Header.Write(&b)
+	return strings.ReplaceAll(b.String(), "\r\n", "\n")
+}
+
+// HAR returns the request and response as a HAR 1.2 log with a single entry,
+// e.g. to import the exchange into the developer tools of a browser
+func (r *Response) HAR() ([]byte, error) {
+	_, statusText, _ := strings.Cut(r.Status, " ")
+	content := harBody(r.Body, r.Header.Get("Content-Type"))
+	if r.FullSize > int64(len(r.Body)) {
+		content.Size = int(r.FullSize)
+	}
+	started := r.Timing.Start
+	if started.IsZero() {
+		started = time.Now()
+	}
+	entry := harEntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Time:            milliseconds(r.Timing.Total),
+		Response: harResponse{
+			Status:      r.StatusCode,
+			StatusText:  statusText,
+			HTTPVersion: r.Proto,
+			Cookies:     harCookies(r.Cookies),
+			Headers:     harHeaders(r.Header),
+			Content:     content,
+			RedirectURL: r.Header.Get("Location"),
+			HeadersSize: r.HeaderSize(),
+			// -1 when the size of the whole body is unknown
+			BodySize: int(r.FullSize),
+		},
+		Timings: harTimingsOf(r.Timing),
+	}
+
+	if rq := r.Request; rq != nil {
+		entry.Request = harRequest{
+			Method:      rq.Method,
+			URL:         rq.URL.String(),
+			HTTPVersion: rq.Proto,
+			Cookies:     harCookies(rq.Cookies()),
+			Headers:     harHeaders(rq.Header),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    0,
+		}
+		if rq.Host != "" && rq.Host != rq.URL.Host {
+			entry.Request.Headers = append([]harNameValue{{"Host", rq.Host}}, entry.Request.Headers...)
+		}
+		for name, values := range rq.URL.Query() {
+			for _, value := range values {
+				entry.Request.QueryString = append(entry.Request.QueryString, harNameValue{name, value})
+			}
+		}
+		slices.SortStableFunc(entry.Request.QueryString, func(a, b harNameValue) int { return strings.Compare(a.Name, b.Name) })
+		// the body was sent, GetBody reads it again
+		if rq.GetBody != nil {
+			if body, err := rq.GetBody(); err == nil {
+				data, err := io.ReadAll(body)
+				body.Close()
+				if err != nil {
+					return nil, err
+				}
+				if len(data) > 0 {
+					// HAR has no encoding of the request body, it is kept as text
+					entry.Request.PostData = &harPostData{
+						MimeType: rq.Header.Get("Content-Type"),
+						Text:     strings.ToValidUTF8(string(data), string(utf8.RuneError)),
+					}
+				}
+				entry.Request.BodySize = len(data)
+			}
+		}
+	}
+
+	har := harLog{Log: harLogBody{
+		Version: "1.2",
+		Creator: harNameVersion{Name: "GHOSTman", Version: "2"},
+		Entries: []harEntry{entry},
+	}}
+	return json.MarshalIndent(har, "", "    ")
+}
+
+// milliseconds returns d in milliseconds, the unit of HAR times
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+type harLog struct {
+	Log harLogBody `json:"log"`
+}
+
+type harLogBody struct {
+	Version string         `json:"version"`
+	Creator harNameVersion `json:"creator"`
+	Entries []harEntry     `json:"entries"`
+}
+
+type harNameVersion struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harCookie    `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harCookie    `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	// Encoding is "base64" for a body that is not text
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// harTimings are the phases of a request in milliseconds, -1 when they do
+// not apply, e.g. on a reused connection
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+func harHeaders(header http.Header) []harNameValue {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	headers := []harNameValue{}
+	for _, name := range names {
+		for _, value := range header[name] {
+			headers = append(headers, harNameValue{name, value})
+		}
+	}
+	return headers
+}
+
+func harCookies(cookies []*http.Cookie) []harCookie {
+	list := []harCookie{}
+	for _, c := range cookies {
+		cookie := harCookie{Name: c.Name, Value: c.Value, Path: c.Path, Domain: c.Domain, HTTPOnly: c.HttpOnly, Secure: c.Secure}
+		if !c.Expires.IsZero() {
+			cookie.Expires = c.Expires.UTC().Format(time.RFC3339)
+		}
+		list = append(list, cookie)
+	}
+	return list
+}
+
+// harBody returns data as HAR content, base64 encoded unless it is text
+func harBody(data []byte, contentType string) harContent {
+	content := harContent{Size: len(data), MimeType: contentType, Text: string(data)}
+	if !isText(data) {
+		content.Text, content.Encoding = base64.StdEncoding.EncodeToString(data), "base64"
+	}
+	return content
+}
+
+// harTimingsOf converts timing to HAR timings. The TLS handshake is part of
+// the connect phase in HAR, send is from the connection to the request
+// being written.
+func harTimingsOf(timing Timing) harTimings {
+	phase := func(p Phase) float64 {
+		if p.End == 0 {
+			return -1
+		}
+		return milliseconds(p.Duration())
+	}
+	t := harTimings{
+		Blocked: -1,
+		DNS:     phase(timing.DNS),
+		Connect: -1,
+		Wait:    milliseconds(timing.Wait.Duration()),
+		Receive: milliseconds(timing.Download.Duration()),
+		SSL:     phase(timing.TLSHandshake),
+	}
+	connected := max(timing.DNS.End, timing.Connect.End, timing.TLSHandshake.End)
+	if timing.Connect.End != 0 {
+		t.Connect = milliseconds(connected - timing.Connect.Start)
+	}
+	if timing.Wait.Start >= connected {
+		t.Send = milliseconds(timing.Wait.Start - connected)
+	}
+	return t
+}
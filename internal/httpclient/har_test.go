@@ -0,0 +1,123 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestResponse_Filename(t *testing.T) {
+	tests := []struct {
+		disposition string
+		contentType string
+		url         string
+		want        string
+	}{
+		{`attachment; filename="report.csv"`, "text/csv", "http://x/export", "report.csv"},
+		{`attachment; filename*=UTF-8''%D0%BE%D1%82%D1%87%D0%B5%D1%82.pdf`, "", "http://x/", "отчет.pdf"},
+		{`attachment; filename="../../etc/passwd"`, "", "http://x/", "passwd"},
+		{"", "application/json; charset=utf-8", "http://x/api/users", "users.json"},
+		{"", "image/png", "http://x/", "response.png"},
+		{"", "", "http://x/files/data.bin", "data.bin"},
+		{"", "", "http://x/", "response"},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		resp := &Response{
+			Header:  http.Header{"Content-Disposition": {tt.disposition}, "Content-Type": {tt.contentType}},
+			Request: &http.Request{URL: u},
+		}
+		if got := resp.Filename(); got != tt.want {
+			t.Errorf("Filename() with %q %q %q = %q, want %q", tt.disposition, tt.contentType, tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestResponse_HAR(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", HttpOnly: true})
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, `{"id":1}`)
+	}))
+	defer ts.Close()
+
+	rq, err := NewRequest(context.Background(), "POST", ts.URL+"/items?b=2&a=1", `{"name":"x"}`, "Content-Type: application/json\nX-Trace: 1", nil)
+	if err != nil {
+		t.Fatalf("NewRequest error: %v", err)
+	}
+	resp, err := Send(rq)
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if got := resp.HeaderText(); got == "" || got[len(got)-1] != '\n' {
+		t.Errorf("HeaderText() = %q", got)
+	}
+
+	data, err := resp.HAR()
+	if err != nil {
+		t.Fatalf("HAR error: %v", err)
+	}
+	var har struct {
+		Log struct {
+			Version string
+			Entries []struct {
+				Time    float64
+				Request struct {
+					Method      string
+					URL         string
+					Headers     []harNameValue
+					QueryString []harNameValue
+					PostData    harPostData
+					BodySize    int
+				}
+				Response struct {
+					Status     int
+					StatusText string
+					Cookies    []harCookie
+					Content    harContent
+					BodySize   int
+				}
+				Timings harTimings
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &har); err != nil {
+		t.Fatalf("invalid HAR: %v\n%s", err, data)
+	}
+	if har.Log.Version != "1.2" || len(har.Log.Entries) != 1 {
+		t.Fatalf("unexpected HAR log:\n%s", data)
+	}
+	entry := har.Log.Entries[0]
+	if entry.Request.Method != "POST" || entry.Request.URL != ts.URL+"/items?b=2&a=1" || entry.Request.BodySize != 12 {
+		t.Errorf("unexpected request: %+v", entry.Request)
+	}
+	if entry.Request.PostData.Text != `{"name":"x"}` || entry.Request.PostData.MimeType != "application/json" {
+		t.Errorf("unexpected post data: %+v", entry.Request.PostData)
+	}
+	if q := entry.Request.QueryString; len(q) != 2 || q[0] != (harNameValue{"a", "1"}) || q[1] != (harNameValue{"b", "2"}) {
+		t.Errorf("unexpected query string: %+v", q)
+	}
+	if entry.Response.Status != 201 || entry.Response.StatusText != "Created" || entry.Response.BodySize != 8 {
+		t.Errorf("unexpected response: %+v", entry.Response)
+	}
+	if c := entry.Response.Content; c.Text != `{"id":1}` || c.MimeType != "application/json" || c.Encoding != "" {
+		t.Errorf("unexpected content: %+v", c)
+	}
+	if len(entry.Response.Cookies) != 1 || !entry.Response.Cookies[0].HTTPOnly {
+		t.Errorf("unexpected cookies: %+v", entry.Response.Cookies)
+	}
+	if entry.Time <= 0 || entry.Timings.Wait <= 0 || entry.Timings.Connect < 0 || entry.Timings.SSL != -1 {
+		t.Errorf("unexpected timings: %v %+v", entry.Time, entry.Timings)
+	}
+}
+
+func TestHARBody(t *testing.T) {
+	if c := harBody([]byte{0x89, 'P', 'N', 'G', 0}, "image/png"); c.Encoding != "base64" || c.Text != "iVBORwA=" || c.Size != 5 {
+		t.Errorf("harBody(binary) = %+v", c)
+	}
+}
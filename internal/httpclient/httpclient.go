@@ -2,7 +2,7 @@ package httpclient
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,44 +11,117 @@ import (
 )
 
 var (
-	// Shared HTTP client with timeout
+	// Client sends the requests without Settings, see WithSettings
 	Client = &http.Client{
 		Timeout: 10 * time.Second,
 	}
 )
 
-// SendRequest sends an HTTP request and returns status, body, and error
-func SendRequest(rq *http.Request) (statusLine string, prettyBody string, isError bool, err error) {
-	resp, err := Client.Do(rq)
+// Send sends rq and returns its response with the body read, up to the
+// MaxResponseSize of its settings, see Response.Truncated. It stops when the context
+// of rq is done, the error then wraps the error of the context, e.g.
+// context.Canceled.
+func Send(rq *http.Request) (*Response, error) {
+	timing, _ := rq.Context().Value(timingKey{}).(*timingRecorder)
+	if timing != nil {
+		rq = timing.trace(rq)
+		defer timing.done()
+	}
+	resp, err := do(rq)
 	if err != nil {
-		return "", "", true, fmt.Errorf("error sending request: %v", err)
+		return nil, fmt.Errorf("error sending request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	const maxResponseSize = 2 * 1024 * 1024 // 2 MB
-	limitedReader := io.LimitReader(resp.Body, maxResponseSize)
-	bodyRS, err := io.ReadAll(limitedReader)
+	limit, keep := bodyLimitFor(rq)
+	bodyRS, size, file, err := readBody(resp.Body, limit, keep)
 	if err != nil {
-		return "", "", true, fmt.Errorf("error reading response: %v", err)
+		return nil, fmt.Errorf("error reading response: %w", err)
 	}
+	if timing != nil {
+		timing.done()
+	}
+	r := newResponse(resp, bodyRS)
+	r.FullSize, r.BodyFile = size, file
+	return r, nil
+}
 
-	statusLine = fmt.Sprintf("HTTP %d %s\n", resp.StatusCode, resp.Status)
+// SendRequest sends an HTTP request and returns status, body, and error.
+// The body of a successful JSON response is indented. See Send for the
+// whole response.
+func SendRequest(rq *http.Request) (statusLine string, prettyBody string, isError bool, err error) {
+	resp, err := Send(rq)
+	if err != nil {
+		return "", "", true, err
+	}
+	if resp.IsError() {
+		return resp.StatusLine(), string(resp.Body), true, nil
+	}
+	return resp.StatusLine(), resp.PrettyBody(), false, nil
+}
 
-	if resp.StatusCode >= 400 {
-		return statusLine, string(bodyRS), true, nil
+// do sends rq and sends it once more when its authenticator asks to retry
+// after the response
+func do(rq *http.Request) (*http.Response, error) {
+	client, err := clientFor(rq)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(rq)
+	if err != nil {
+		return nil, err
+	}
+	state, _ := rq.Context().Value(authStateKey{}).(*authState)
+	if state == nil || resp.StatusCode < 400 {
+		return resp, nil
+	}
+	auth, ok := state.auth.(RetryAuthenticator)
+	if !ok {
+		return resp, nil
 	}
 
-	var prettyJSON bytes.Buffer
-	if err := json.Indent(&prettyJSON, bodyRS, "", "    "); err != nil {
-		return statusLine, string(bodyRS), false, nil
+	retry := rq.Clone(rq.Context())
+	if rq.GetBody != nil {
+		if retry.Body, err = rq.GetBody(); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+	again, err := auth.Reauthenticate(retry, resp)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if !again {
+		return resp, nil
 	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	resp.Body.Close()
+	state.challenged = true
+	return client.Do(retry)
+}
 
-	return statusLine, prettyJSON.String(), false, nil
+// Challenged reports whether SendRequest answered an authentication
+// challenge of the server to rq by sending it once more
+func Challenged(rq *http.Request) bool {
+	state, _ := rq.Context().Value(authStateKey{}).(*authState)
+	return state != nil && state.challenged
 }
 
-// NewRequest creates a new http.Request from method, url, body, and headers string
-func NewRequest(method, url, body, headers string) (*http.Request, error) {
-	rq, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+// NewRequest creates a new http.Request from method, url, body, and headers string.
+// headers has a "Key: Value" line per header, a key may be repeated.
+// auth, when not nil, adds its credentials after the headers are set and is
+// kept with the request so SendRequest can retry when it is rejected.
+// Cancelling ctx stops fetching credentials and sending the request.
+func NewRequest(ctx context.Context, method, url, body, headers string, auth Authenticator) (*http.Request, error) {
+	return NewRequestWithBody(ctx, method, url, Body{Data: []byte(body)}, headers, auth)
+}
+
+// NewRequestWithBody is like NewRequest with an encoded body. The content type
+// of the body is sent unless headers set one.
+func NewRequestWithBody(ctx context.Context, method, url string, body Body, headers string, auth Authenticator) (*http.Request, error) {
+	ctx = context.WithValue(ctx, timingKey{}, &timingRecorder{})
+	rq, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body.Data))
 	if err != nil {
 		return nil, err
 	}
@@ -58,8 +131,25 @@ func NewRequest(method, url, body, headers string) (*http.Request, error) {
 			continue
 		}
 		parts := strings.SplitN(h, ":", 2)
-		if len(parts) == 2 {
-			rq.Header.Set(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+		if len(parts) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if strings.EqualFold(key, "Host") {
+			// net/http sends rq.Host, not the Host header
+			rq.Host = value
+			continue
+		}
+		// repeated lines send every value, e.g. several Accept headers
+		rq.Header.Add(key, value)
+	}
+	if body.ContentType != "" && rq.Header.Get("Content-Type") == "" {
+		rq.Header.Set("Content-Type", body.ContentType)
+	}
+	if auth != nil {
+		rq = rq.WithContext(context.WithValue(rq.Context(), authStateKey{}, &authState{auth: auth}))
+		if err := auth.Authenticate(rq); err != nil {
+			return nil, err
 		}
 	}
 	return rq, nil
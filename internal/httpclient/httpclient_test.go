@@ -1,14 +1,17 @@
 package httpclient
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewRequest(t *testing.T) {
-	rq, err := NewRequest("POST", "http://example.com", "body", "X-Test: 123\nContent-Type: text/plain")
+	rq, err := NewRequest(context.Background(), "POST", "http://example.com", "body", "X-Test: 123\nContent-Type: text/plain", nil)
 	if err != nil {
 		t.Fatalf("NewRequest error: %v", err)
 	}
@@ -20,6 +23,22 @@ func TestNewRequest(t *testing.T) {
 	}
 }
 
+func TestNewRequestRepeatedHeaders(t *testing.T) {
+	rq, err := NewRequest(context.Background(), "GET", "http://example.com", "", "Accept: application/json\nCookie: a=1\nAccept: text/plain\nCookie: b=2\nHost: api.example.com", nil)
+	if err != nil {
+		t.Fatalf("NewRequest error: %v", err)
+	}
+	if got := rq.Header.Values("Accept"); len(got) != 2 || got[0] != "application/json" || got[1] != "text/plain" {
+		t.Errorf("Accept = %q, want both values", got)
+	}
+	if got := rq.Header.Values("Cookie"); len(got) != 2 {
+		t.Errorf("Cookie = %q, want both values", got)
+	}
+	if rq.Host != "api.example.com" || rq.Header.Get("Host") != "" {
+		t.Errorf("Host = %q, header %q, want the request host set", rq.Host, rq.Header.Get("Host"))
+	}
+}
+
 func TestSendRequest(t *testing.T) {
 	// Мок-сервер
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -33,7 +52,7 @@ func TestSendRequest(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	rq, _ := NewRequest("GET", ts.URL, "", "")
+	rq, _ := NewRequest(context.Background(), "GET", ts.URL, "", "", nil)
 	status, body, isErr, err := SendRequest(rq)
 	if err != nil || isErr {
 		t.Errorf("unexpected error: %v", err)
@@ -42,9 +61,67 @@ func TestSendRequest(t *testing.T) {
 		t.Errorf("unexpected status/body: %s %s", status, body)
 	}
 
-	rq2, _ := NewRequest("GET", ts.URL+"/err", "", "")
+	rq2, _ := NewRequest(context.Background(), "GET", ts.URL+"/err", "", "", nil)
 	status2, body2, isErr2, err2 := SendRequest(rq2)
 	if err2 != nil || !isErr2 || !strings.Contains(status2, "500") || !strings.Contains(body2, "fail") {
 		t.Errorf("unexpected error or response: %v %v %s %s", isErr2, err2, status2, body2)
 	}
 }
+
+func TestNewRequestAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		auth   Authenticator
+		header string
+		want   string
+		query  string
+	}{
+		{name: "basic", url: "http://example.com", auth: BasicAuth{Username: "user", Password: "pass"}, header: "Authorization", want: "Basic dXNlcjpwYXNz"},
+		{name: "bearer", url: "http://example.com", auth: BearerAuth{Token: "abc"}, header: "Authorization", want: "Bearer abc"},
+		{name: "api key header", url: "http://example.com", auth: APIKeyAuth{Key: "X-API-Key", Value: "secret"}, header: "X-API-Key", want: "secret"},
+		{name: "api key query", url: "http://example.com/?a=1", auth: APIKeyAuth{Key: "api key", Value: "s&t", InQuery: true}, query: "a=1&api+key=s%26t"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rq, err := NewRequest(context.Background(), "GET", tt.url, "", "Authorization: overridden", tt.auth)
+			if err != nil {
+				t.Fatalf("NewRequest error: %v", err)
+			}
+			if tt.header != "" && rq.Header.Get(tt.header) != tt.want {
+				t.Errorf("header %s = %q, want %q", tt.header, rq.Header.Get(tt.header), tt.want)
+			}
+			if tt.query != "" && rq.URL.RawQuery != tt.query {
+				t.Errorf("query = %q, want %q", rq.URL.RawQuery, tt.query)
+			}
+		})
+	}
+}
+
+func TestSendRequestCancelled(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rq, err := NewRequest(ctx, "GET", ts.URL, "", "", nil)
+	if err != nil {
+		t.Fatalf("NewRequest error: %v", err)
+	}
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, _, isErr, err := SendRequest(rq)
+	if !isErr || !errors.Is(err, context.Canceled) {
+		t.Errorf("SendRequest error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("SendRequest returned after %s, want right after the cancel", elapsed)
+	}
+}
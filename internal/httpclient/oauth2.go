@@ -0,0 +1,294 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OAuth2 grant types. Postman calls the password grant "password_credentials".
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantPassword          = "password"
+	GrantRefreshToken      = "refresh_token"
+)
+
+// tokenExpiryDelta is how long before its expiry a token is renewed
+const tokenExpiryDelta = 10 * time.Second
+
+// Token is an OAuth2 access token
+type Token struct {
+	AccessToken  string
+	TokenType    string
+	RefreshToken string
+	// Expiry is zero when the token endpoint did not say
+	Expiry time.Time
+}
+
+// valid reports whether the token can still be used
+func (t *Token) valid() bool {
+	return t != nil && t.AccessToken != "" && (t.Expiry.IsZero() || time.Now().Add(tokenExpiryDelta).Before(t.Expiry))
+}
+
+// TokenCache keeps OAuth2 tokens until they expire. Tokens are kept by
+// environment, token endpoint, grant and credentials once variables are
+// resolved, so switching environments never sends a token of another one.
+type TokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]*Token
+	// fetching holds a token of each key while its token is fetched, so
+	// concurrent requests fetch it once
+	fetching map[string]chan struct{}
+}
+
+// NewTokenCache returns an empty token cache
+func NewTokenCache() *TokenCache {
+	return &TokenCache{tokens: make(map[string]*Token), fetching: make(map[string]chan struct{})}
+}
+
+// get returns the token of key and the guard of its fetch
+func (c *TokenCache) get(key string) (*Token, chan struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	guard := c.fetching[key]
+	if guard == nil {
+		guard = make(chan struct{}, 1)
+		c.fetching[key] = guard
+	}
+	return c.tokens[key], guard
+}
+
+// set keeps token as the token of key, none when token is nil
+func (c *TokenCache) set(key string, token *Token) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if token == nil {
+		delete(c.tokens, key)
+		return
+	}
+	c.tokens[key] = token
+}
+
+// DefaultTokenCache is used by OAuth2Auth without a cache of its own
+var DefaultTokenCache = NewTokenCache()
+
+// OAuth2Auth authenticates with an access token fetched from a token
+// endpoint. The token is cached, renewed when it expires and fetched again
+// when the server answers 401 Unauthorized.
+type OAuth2Auth struct {
+	TokenURL     string
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+	// ClientInBody sends the client credentials in the request body instead
+	// of a basic auth header
+	ClientInBody bool
+	Username     string
+	Password     string
+	Scope        string
+	RefreshToken string
+	// HeaderPrefix is the scheme of the Authorization header, "Bearer" when empty
+	HeaderPrefix string
+	// InQuery sends the token as the access_token query parameter
+	InQuery bool
+	// Environment is the name of the environment the settings are resolved
+	// with, tokens are not shared across environments
+	Environment string
+	Cache       *TokenCache
+}
+
+// Authenticate implements Authenticator
+func (a *OAuth2Auth) Authenticate(rq *http.Request) error {
+	token, err := a.token(rq.Context(), false)
+	if err != nil {
+		return err
+	}
+	a.apply(rq, token)
+	return nil
+}
+
+// Reauthenticate implements RetryAuthenticator by fetching a new token
+func (a *OAuth2Auth) Reauthenticate(rq *http.Request, resp *http.Response) (bool, error) {
+	if resp.StatusCode != http.StatusUnauthorized {
+		return false, nil
+	}
+	token, err := a.token(rq.Context(), true)
+	if err != nil {
+		return false, err
+	}
+	a.apply(rq, token)
+	return true, nil
+}
+
+func (a *OAuth2Auth) apply(rq *http.Request, token *Token) {
+	if a.InQuery {
+		q := rq.URL.Query()
+		q.Set("access_token", token.AccessToken)
+		rq.URL.RawQuery = q.Encode()
+		return
+	}
+	prefix := a.HeaderPrefix
+	if prefix == "" {
+		prefix = "Bearer"
+	}
+	rq.Header.Set("Authorization", prefix+" "+token.AccessToken)
+}
+
+func (a *OAuth2Auth) cache() *TokenCache {
+	if a.Cache != nil {
+		return a.Cache
+	}
+	return DefaultTokenCache
+}
+
+func (a *OAuth2Auth) cacheKey() string {
+	return strings.Join([]string{a.Environment, a.TokenURL, a.GrantType, a.ClientID, a.ClientSecret, a.Username, a.Password, a.Scope, a.RefreshToken}, "\x00")
+}
+
+// token returns the cached token or fetches a new one when there is none,
+// it expired or renew is set. An expired token with a refresh token is
+// refreshed first. Only one token of a key is fetched at a time, requests
+// needing it meanwhile wait for it without blocking the other keys.
+func (a *OAuth2Auth) token(ctx context.Context, renew bool) (*Token, error) {
+	cache := a.cache()
+	key := a.cacheKey()
+	stale, guard := cache.get(key)
+	if !renew && stale.valid() {
+		return stale, nil
+	}
+
+	select {
+	case guard <- struct{}{}:
+		defer func() { <-guard }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	// the token may have been fetched while waiting
+	cached, _ := cache.get(key)
+	if cached.valid() && (!renew || cached != stale) {
+		return cached, nil
+	}
+
+	var token *Token
+	var err error
+	if cached != nil && cached.RefreshToken != "" {
+		token, err = a.fetch(ctx, a.refreshParams(cached.RefreshToken))
+	}
+	if token == nil {
+		var params url.Values
+		if params, err = a.grantParams(); err != nil {
+			return nil, err
+		}
+		if token, err = a.fetch(ctx, params); err != nil {
+			cache.set(key, nil)
+			return nil, err
+		}
+	}
+	if token.RefreshToken == "" && cached != nil {
+		token.RefreshToken = cached.RefreshToken
+	}
+	cache.set(key, token)
+	return token, nil
+}
+
+// grantParams returns the token request parameters of the configured grant
+func (a *OAuth2Auth) grantParams() (url.Values, error) {
+	params := url.Values{}
+	switch a.GrantType {
+	case GrantClientCredentials, "":
+		params.Set("grant_type", GrantClientCredentials)
+	case GrantPassword, "password_credentials":
+		params.Set("grant_type", GrantPassword)
+		params.Set("username", a.Username)
+		params.Set("password", a.Password)
+	case GrantRefreshToken:
+		return a.refreshParams(a.RefreshToken), nil
+	default:
+		return nil, fmt.Errorf("unsupported OAuth2 grant type %q", a.GrantType)
+	}
+	if a.Scope != "" {
+		params.Set("scope", a.Scope)
+	}
+	return params, nil
+}
+
+func (a *OAuth2Auth) refreshParams(refreshToken string) url.Values {
+	params := url.Values{}
+	params.Set("grant_type", GrantRefreshToken)
+	params.Set("refresh_token", refreshToken)
+	if a.Scope != "" {
+		params.Set("scope", a.Scope)
+	}
+	return params
+}
+
+// tokenResponse is the token endpoint response, RFC 6749 section 5
+type tokenResponse struct {
+	AccessToken      string      `json:"access_token"`
+	TokenType        string      `json:"token_type"`
+	ExpiresIn        json.Number `json:"expires_in"`
+	RefreshToken     string      `json:"refresh_token"`
+	Error            string      `json:"error"`
+	ErrorDescription string      `json:"error_description"`
+}
+
+// fetch requests a token from the token endpoint
+func (a *OAuth2Auth) fetch(ctx context.Context, params url.Values) (*Token, error) {
+	if a.ClientInBody {
+		params.Set("client_id", a.ClientID)
+		if a.ClientSecret != "" {
+			params.Set("client_secret", a.ClientSecret)
+		}
+	}
+	rq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.TokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("error requesting OAuth2 token: %v", err)
+	}
+	rq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rq.Header.Set("Accept", "application/json")
+	if !a.ClientInBody && a.ClientID != "" {
+		rq.SetBasicAuth(url.QueryEscape(a.ClientID), url.QueryEscape(a.ClientSecret))
+	}
+
+	// the token is requested with the settings of the request it is for
+	client, err := clientFor(rq)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting OAuth2 token: %v", err)
+	}
+	resp, err := client.Do(rq)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting OAuth2 token: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("error reading OAuth2 token: %v", err)
+	}
+
+	var tr tokenResponse
+	jsonErr := json.Unmarshal(body, &tr)
+	if resp.StatusCode >= 400 || tr.Error != "" {
+		if tr.Error != "" {
+			return nil, fmt.Errorf("OAuth2 token request failed: %s %s", tr.Error, tr.ErrorDescription)
+		}
+		return nil, fmt.Errorf("OAuth2 token request failed: %s", resp.Status)
+	}
+	if jsonErr != nil {
+		return nil, fmt.Errorf("error parsing OAuth2 token: %v", jsonErr)
+	}
+	if tr.AccessToken == "" {
+		return nil, fmt.Errorf("error parsing OAuth2 token: no access_token in response")
+	}
+
+	token := &Token{AccessToken: tr.AccessToken, TokenType: tr.TokenType, RefreshToken: tr.RefreshToken}
+	if seconds, err := tr.ExpiresIn.Int64(); err == nil && seconds > 0 {
+		token.Expiry = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	return token, nil
+}
@@ -0,0 +1,250 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// tokenServer issues tokens t1, t2, ... and records the token requests
+type tokenServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	issued    int
+	expiresIn int
+	requests  []map[string]string
+}
+
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	ts := &tokenServer{expiresIn: expiresIn}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm error: %v", err)
+		}
+		params := map[string]string{}
+		for k := range r.PostForm {
+			params[k] = r.PostForm.Get(k)
+		}
+		if user, pass, ok := r.BasicAuth(); ok {
+			params["basic"] = user + ":" + pass
+		}
+
+		ts.mu.Lock()
+		defer ts.mu.Unlock()
+		ts.requests = append(ts.requests, params)
+		if params["basic"] == "id:wrong" || params["client_secret"] == "wrong" {
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"error":"invalid_client","error_description":"bad secret"}`)
+			return
+		}
+		ts.issued++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"t%d","token_type":"Bearer","expires_in":%d,"refresh_token":"r%d"}`, ts.issued, ts.expiresIn, ts.issued)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// apiServer accepts requests bearing the token returned by valid
+func newAPIServer(t *testing.T, valid func() string) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+valid() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, `{"body":%q}`, body)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	api := newAPIServer(t, func() string { return "t1" })
+	auth := &OAuth2Auth{
+		TokenURL:     tokens.URL,
+		GrantType:    GrantClientCredentials,
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Scope:        "read",
+		Cache:        NewTokenCache(),
+	}
+
+	for i := 0; i < 2; i++ {
+		rq, err := NewRequest(context.Background(), "GET", api.URL, "", "", auth)
+		if err != nil {
+			t.Fatalf("NewRequest error: %v", err)
+		}
+		status, _, isErr, err := SendRequest(rq)
+		if err != nil || isErr {
+			t.Fatalf("SendRequest = %q, %v", status, err)
+		}
+	}
+
+	if len(tokens.requests) != 1 {
+		t.Fatalf("token requests = %d, want 1 as the token is cached", len(tokens.requests))
+	}
+	got := tokens.requests[0]
+	if got["grant_type"] != "client_credentials" || got["scope"] != "read" || got["basic"] != "id:secret" {
+		t.Errorf("unexpected token request: %v", got)
+	}
+}
+
+func TestOAuth2TokenPerEnvironment(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	cache := NewTokenCache()
+
+	for _, tc := range []struct{ env, token string }{{"dev", "t1"}, {"prod", "t2"}, {"dev", "t1"}} {
+		auth := &OAuth2Auth{TokenURL: tokens.URL, ClientID: "id", ClientSecret: "secret", Environment: tc.env, Cache: cache}
+		rq, err := NewRequest(context.Background(), "GET", "http://example.com", "", "", auth)
+		if err != nil {
+			t.Fatalf("NewRequest error: %v", err)
+		}
+		if got, want := rq.Header.Get("Authorization"), "Bearer "+tc.token; got != want {
+			t.Errorf("%s: Authorization = %q, want %q", tc.env, got, want)
+		}
+	}
+
+	if len(tokens.requests) != 2 {
+		t.Fatalf("token requests = %d, want 2, one per environment", len(tokens.requests))
+	}
+}
+
+func TestOAuth2RefreshOnUnauthorized(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	var mu sync.Mutex
+	valid := "t1"
+	api := newAPIServer(t, func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return valid
+	})
+	auth := &OAuth2Auth{TokenURL: tokens.URL, ClientID: "id", ClientSecret: "secret", ClientInBody: true, Cache: NewTokenCache()}
+
+	rq, err := NewRequest(context.Background(), "POST", api.URL, "payload", "", auth)
+	if err != nil {
+		t.Fatalf("NewRequest error: %v", err)
+	}
+	if _, _, isErr, err := SendRequest(rq); err != nil || isErr {
+		t.Fatalf("SendRequest error: %v", err)
+	}
+
+	// the server revokes t1, the cached token is rejected once and renewed
+	mu.Lock()
+	valid = "t2"
+	mu.Unlock()
+	rq, err = NewRequest(context.Background(), "POST", api.URL, "payload", "", auth)
+	if err != nil {
+		t.Fatalf("NewRequest error: %v", err)
+	}
+	status, body, isErr, err := SendRequest(rq)
+	if err != nil || isErr {
+		t.Fatalf("SendRequest = %q, %v", status, err)
+	}
+	if !strings.Contains(body, "payload") {
+		t.Errorf("retried request lost its body: %s", body)
+	}
+
+	if len(tokens.requests) != 2 {
+		t.Fatalf("token requests = %d, want 2", len(tokens.requests))
+	}
+	if got := tokens.requests[1]; got["grant_type"] != "refresh_token" || got["refresh_token"] != "r1" || got["client_id"] != "id" {
+		t.Errorf("unexpected refresh request: %v", got)
+	}
+}
+
+func TestOAuth2PasswordRefreshesExpiredToken(t *testing.T) {
+	// tokens expiring within tokenExpiryDelta are renewed before use
+	tokens := newTokenServer(t, 1)
+	auth := &OAuth2Auth{
+		TokenURL:  tokens.URL,
+		GrantType: "password_credentials",
+		ClientID:  "id",
+		Username:  "user",
+		Password:  "pass",
+		Cache:     NewTokenCache(),
+	}
+
+	for _, want := range []string{"Bearer t1", "Bearer t2"} {
+		rq, err := NewRequest(context.Background(), "GET", "http://example.com", "", "", auth)
+		if err != nil {
+			t.Fatalf("NewRequest error: %v", err)
+		}
+		if got := rq.Header.Get("Authorization"); got != want {
+			t.Errorf("Authorization = %q, want %q", got, want)
+		}
+	}
+
+	if got := tokens.requests[0]; got["grant_type"] != "password" || got["username"] != "user" || got["password"] != "pass" {
+		t.Errorf("unexpected password request: %v", got)
+	}
+	if got := tokens.requests[1]; got["grant_type"] != "refresh_token" || got["refresh_token"] != "r1" {
+		t.Errorf("unexpected refresh request: %v", got)
+	}
+}
+
+func TestOAuth2TokenError(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	auth := &OAuth2Auth{TokenURL: tokens.URL, ClientID: "id", ClientSecret: "wrong", Cache: NewTokenCache()}
+
+	_, err := NewRequest(context.Background(), "GET", "http://example.com", "", "", auth)
+	if err == nil || !strings.Contains(err.Error(), "invalid_client") {
+		t.Errorf("NewRequest error = %v, want invalid_client", err)
+	}
+}
+
+func TestOAuth2ConcurrentFetch(t *testing.T) {
+	// the slow endpoint answers its token requests once release is closed
+	release := make(chan struct{})
+	requested := make(chan struct{}, 2)
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested <- struct{}{}
+		<-release
+		io.WriteString(w, `{"access_token":"slow","expires_in":3600}`)
+	}))
+	defer slow.Close()
+	tokens := newTokenServer(t, 3600)
+	cache := NewTokenCache()
+
+	slowAuth := &OAuth2Auth{TokenURL: slow.URL, ClientID: "id", Cache: cache}
+	results := make(chan string, 2)
+	for range 2 {
+		go func() {
+			rq, err := NewRequest(context.Background(), "GET", "http://example.com", "", "", slowAuth)
+			if err != nil {
+				results <- err.Error()
+				return
+			}
+			results <- rq.Header.Get("Authorization")
+		}()
+	}
+
+	<-requested
+	// another token endpoint is not blocked by the slow one
+	if _, err := NewRequest(context.Background(), "GET", "http://example.com", "", "", &OAuth2Auth{TokenURL: tokens.URL, ClientID: "id", Cache: cache}); err != nil {
+		t.Fatalf("NewRequest error: %v", err)
+	}
+	// nor is a request giving up
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewRequest(ctx, "GET", "http://example.com", "", "", slowAuth); err == nil {
+		t.Error("NewRequest with a cancelled context waited for the token")
+	}
+
+	close(release)
+	for range 2 {
+		if got := <-results; got != "Bearer slow" {
+			t.Errorf("Authorization = %q, want Bearer slow", got)
+		}
+	}
+	if n := len(requested); n != 0 {
+		t.Errorf("token requests = %d, want 1 for concurrent requests", n+1)
+	}
+}
@@ -0,0 +1,221 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"html"
+	"image"
+	_ "image/gif" // decoders of the images shown
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// BodyKind is the kind of content of a response body, choosing how it is shown
+type BodyKind int
+
+// Kinds of response bodies
+const (
+	KindText BodyKind = iota
+	KindJSON
+	KindXML
+	KindHTML
+	KindImage
+	KindBinary
+)
+
+// Kind returns the kind of the body from its Content-Type, sniffing the
+// content when the type is missing or generic
+func (r *Response) Kind() BodyKind {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "" || mediaType == "application/octet-stream" {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(r.Body))
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return KindJSON
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		return KindHTML
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return KindXML
+	case strings.HasPrefix(mediaType, "image/"):
+		if _, _, err := image.DecodeConfig(bytes.NewReader(r.Body)); err == nil {
+			return KindImage
+		}
+		return KindBinary
+	case strings.HasPrefix(mediaType, "text/") || mediaType == "application/javascript":
+		if json.Valid(r.Body) && len(bytes.TrimSpace(r.Body)) > 0 {
+			return KindJSON
+		}
+		return KindText
+	case isText(r.Body):
+		return KindText
+	}
+	return KindBinary
+}
+
+// isText reports whether data is UTF-8 text without control characters
+// other than white space
+func isText(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Text returns the body as text that a text widget can show: invalid UTF-8
+// and control characters other than white space are replaced by U+FFFD
+func (r *Response) Text() string {
+	return text(r.Body)
+}
+
+// text returns data as text, see Response.Text
+func text(data []byte) string {
+	return strings.Map(func(c rune) rune {
+		if unicode.IsControl(c) && !unicode.IsSpace(c) {
+			return utf8.RuneError
+		}
+		return c
+	}, strings.ToValidUTF8(string(data), string(utf8.RuneError)))
+}
+
+// PrettyXML returns the body as indented XML. Elements holding text only are
+// kept on one line, namespace prefixes are kept as they are.
+func (r *Response) PrettyXML() (string, error) {
+	var b strings.Builder
+	d := xml.NewDecoder(bytes.NewReader(r.Body))
+	depth := 0
+	// open is set while the start tag written last is missing its ">"
+	// and text while the element written last holds text
+	open, text := false, false
+	newline := func() {
+		if b.Len() > 0 {
+			b.WriteString("\n" + strings.Repeat("    ", depth))
+		}
+	}
+	closeStart := func() {
+		if open {
+			b.WriteString(">")
+			open = false
+		}
+	}
+
+	for {
+		token, err := d.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			closeStart()
+			newline()
+			b.WriteString("<" + xmlName(t.Name))
+			for _, attr := range t.Attr {
+				b.WriteString(" " + xmlName(attr.Name) + `="`)
+				xml.EscapeText(&b, []byte(attr.Value))
+				b.WriteString(`"`)
+			}
+			open, text = true, false
+			depth++
+		case xml.EndElement:
+			depth--
+			if open {
+				b.WriteString("/>")
+				open = false
+			} else {
+				if !text {
+					newline()
+				}
+				b.WriteString("</" + xmlName(t.Name) + ">")
+			}
+			text = false
+		case xml.CharData:
+			// white space between elements is replaced by the indentation
+			data := bytes.TrimSpace(t)
+			if len(data) == 0 {
+				continue
+			}
+			closeStart()
+			xml.EscapeText(&b, data)
+			text = true
+		case xml.Comment:
+			closeStart()
+			newline()
+			b.WriteString("<!--" + string(t) + "-->")
+		case xml.ProcInst:
+			newline()
+			b.WriteString("<?" + t.Target + " " + string(t.Inst) + "?>")
+		case xml.Directive:
+			newline()
+			b.WriteString("<!" + string(t) + ">")
+		}
+	}
+	if depth != 0 {
+		return "", errors.New("XML syntax error: unclosed element")
+	}
+	return b.String(), nil
+}
+
+// xmlName returns name as written, with its namespace prefix
+func xmlName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+var (
+	// htmlHidden matches the elements whose content is not shown
+	htmlHidden = regexp.MustCompile(`(?is)<(script|style|head|template)\b.*?</(script|style|head|template)\s*>|<!--.*?-->`)
+	// htmlBreak matches the tags starting a new line
+	htmlBreak = regexp.MustCompile(`(?i)<(br|/?p|/?div|/?h[1-6]|/?li|/?tr|/?table|/?ul|/?ol|/?section|/?article|/?header|/?footer|/?pre|/?blockquote|hr)\b[^>]*>`)
+	htmlTag   = regexp.MustCompile(`<[^>]*>`)
+	// spaces matches the white space collapsed to a single space
+	spaces = regexp.MustCompile(`[ \t\r\f\v]+`)
+	// blankLines matches the empty lines collapsed to one
+	blankLines = regexp.MustCompile(`\n\s*\n\s*`)
+)
+
+// HTMLText returns the text of an HTML body as a text preview of the page
+func (r *Response) HTMLText() string {
+	s := htmlHidden.ReplaceAllString(string(r.Body), "")
+	s = htmlBreak.ReplaceAllString(s, "\n")
+	s = htmlTag.ReplaceAllString(s, "")
+	s = spaces.ReplaceAllString(html.UnescapeString(s), " ")
+	s = blankLines.ReplaceAllString(s, "\n\n")
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// ImageConfig returns the format and dimensions of an image body
+func (r *Response) ImageConfig() (image.Config, string, error) {
+	return image.DecodeConfig(bytes.NewReader(r.Body))
+}
+
+// HexDump returns a hex dump of the body, the offset, the hex bytes and
+// the bytes as text on each line
+func (r *Response) HexDump() string {
+	return hex.Dump(r.Body)
+}
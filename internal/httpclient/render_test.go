@@ -0,0 +1,110 @@
+package httpclient
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// newTestResponse returns a response with body and content type
+func newTestResponse(contentType string, body []byte) *Response {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return &Response{Header: header, Body: body}
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestResponse_Kind(t *testing.T) {
+	pngData := testPNG(t, 3, 2)
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		want        BodyKind
+	}{
+		{"json", "application/json; charset=utf-8", []byte(`{"a":1}`), KindJSON},
+		{"problem json", "application/problem+json", []byte(`{}`), KindJSON},
+		{"json as text", "text/plain", []byte(`[1, 2]`), KindJSON},
+		{"xml", "application/xml", []byte(`<a/>`), KindXML},
+		{"soap", "application/soap+xml", []byte(`<a/>`), KindXML},
+		{"html", "text/html; charset=utf-8", []byte(`<html></html>`), KindHTML},
+		{"png", "image/png", pngData, KindImage},
+		{"sniffed png", "", pngData, KindImage},
+		{"broken image", "image/png", []byte("not a png"), KindBinary},
+		{"text", "text/plain", []byte("hello"), KindText},
+		{"sniffed html", "application/octet-stream", []byte("<!DOCTYPE html><html></html>"), KindHTML},
+		{"untyped text", "", []byte("plain words"), KindText},
+		{"binary", "application/octet-stream", []byte{0, 1, 2, 0xff}, KindBinary},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newTestResponse(tt.contentType, tt.body).Kind(); got != tt.want {
+				t.Errorf("Kind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponse_PrettyXML(t *testing.T) {
+	r := newTestResponse("application/xml", []byte(`<?xml version="1.0"?><soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><!-- note --><item id="1" name="a &amp; b">text &lt;1&gt;</item><empty/></soap:Body></soap:Envelope>`))
+	got, err := r.PrettyXML()
+	if err != nil {
+		t.Fatalf("PrettyXML() error: %v", err)
+	}
+	want := `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+    <soap:Body>
+        <!-- note -->
+        <item id="1" name="a &amp; b">text &lt;1&gt;</item>
+        <empty/>
+    </soap:Body>
+</soap:Envelope>`
+	if got != want {
+		t.Errorf("PrettyXML() =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := newTestResponse("application/xml", []byte(`<a><b></a>`)).PrettyXML(); err == nil {
+		t.Errorf("PrettyXML() of invalid XML succeeded")
+	}
+}
+
+func TestResponse_HTMLText(t *testing.T) {
+	r := newTestResponse("text/html", []byte(`<html><head><title>T</title><style>p{}</style></head>
+<body><h1>Title</h1><script>alert(1)</script><p>Fish &amp; chips,   <b>hot</b></p><!-- hidden --><ul><li>one</li><li>two</li></ul></body></html>`))
+	if got, want := r.HTMLText(), "Title\n\nFish & chips, hot\n\none\n\ntwo"; got != want {
+		t.Errorf("HTMLText() = %q, want %q", got, want)
+	}
+}
+
+func TestResponse_ImageConfig(t *testing.T) {
+	config, format, err := newTestResponse("image/png", testPNG(t, 3, 2)).ImageConfig()
+	if err != nil || format != "png" || config.Width != 3 || config.Height != 2 {
+		t.Errorf("ImageConfig() = %v %q, %v, want a 3x2 png", config, format, err)
+	}
+}
+
+func TestResponse_TextAndHexDump(t *testing.T) {
+	r := newTestResponse("application/octet-stream", []byte("a\x00b\xffc\n"))
+	if got := r.Text(); got != "a�b�c\n" {
+		t.Errorf("Text() = %q", got)
+	}
+	if got := r.HexDump(); !strings.HasPrefix(got, "00000000  61 00 62 ff 63 0a") || !strings.Contains(got, "|a.b.c.|") {
+		t.Errorf("HexDump() = %q", got)
+	}
+}
@@ -0,0 +1,104 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Response is a response received by Send, with its body read
+type Response struct {
+	// Status is the status code and reason, e.g. "200 OK"
+	Status     string
+	StatusCode int
+	// Proto is the protocol of the response, e.g. "HTTP/1.1"
+	Proto   string
+	Header  http.Header
+	Cookies []*http.Cookie
+	// Body is the body read, up to the MaxResponseSize of the Settings
+	Body []byte
+	// FullSize is the size of the whole body, -1 when it is larger than Body
+	// and was not read
+	FullSize int64
+	// BodyFile is the temporary file holding the whole body when it is larger
+	// than Body and the KeepFullResponse of the Settings is set
+	BodyFile string
+	Timing   Timing
+	// TLS is the state of the TLS connection, nil for an HTTP response. Its
+	// PeerCertificates are the chain of the server.
+	TLS *tls.ConnectionState
+	// Request is the request answered, the last one when redirected
+	Request *http.Request
+}
+
+func newResponse(resp *http.Response, body []byte) *Response {
+	r := &Response{
+		Status:     resp.Status,
+		StatusCode: resp.StatusCode,
+		Proto:      resp.Proto,
+		Header:     resp.Header,
+		Cookies:    resp.Cookies(),
+		Body:       body,
+		FullSize:   int64(len(body)),
+		TLS:        resp.TLS,
+		Request:    resp.Request,
+	}
+	if resp.Request != nil {
+		r.Timing, _ = RequestTiming(resp.Request)
+	}
+	return r
+}
+
+// IsError reports whether the status is a client or server error
+func (r *Response) IsError() bool {
+	return r.StatusCode >= 400
+}
+
+// StatusLine returns the status line shown above the body, e.g.
+// "HTTP/1.1 200 OK"
+func (r *Response) StatusLine() string {
+	proto, status := r.Proto, r.Status
+	if proto == "" {
+		proto = "HTTP"
+	}
+	if status == "" {
+		status = fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode))
+	}
+	return proto + " " + status + "\n"
+}
+
+// PrettyBody returns the body, indented when it is JSON
+func (r *Response) PrettyBody() string {
+	var prettyJSON bytes.Buffer
+	if err := json.Indent(&prettyJSON, r.Body, "", "    "); err != nil {
+		return string(r.Body)
+	}
+	return prettyJSON.String()
+}
+
+// Size returns the size of the body read
+func (r *Response) Size() int {
+	return len(r.Body)
+}
+
+// HeaderSize returns the size of the status line and headers as sent on
+// HTTP/1.1
+func (r *Response) HeaderSize() int {
+	return len(r.rawHeader())
+}
+
+// Raw returns the response as it is sent on HTTP/1.1: status line, headers
+// and body
+func (r *Response) Raw() string {
+	return r.rawHeader() + string(r.Body)
+}
+
+func (r *Response) rawHeader() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s %s\r\n", r.Proto, r.Status)
+	r.Header.Write(&b)
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSend(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Add("X-Multi", "a")
+		w.Header().Add("X-Multi", "b")
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/", HttpOnly: true})
+		http.SetCookie(w, &http.Cookie{Name: "theme", Value: "dark", MaxAge: 60})
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, `{"id":1}`)
+	}))
+	defer ts.Close()
+
+	rq, err := NewRequest(context.Background(), "POST", ts.URL+"/items", "", "", nil)
+	if err != nil {
+		t.Fatalf("NewRequest error: %v", err)
+	}
+	resp, err := Send(rq)
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	if resp.StatusCode != 201 || resp.Status != "201 Created" || resp.Proto != "HTTP/1.1" || resp.IsError() {
+		t.Errorf("unexpected status: %d %q %q", resp.StatusCode, resp.Status, resp.Proto)
+	}
+	if got := resp.Header.Values("X-Multi"); len(got) != 2 {
+		t.Errorf("X-Multi = %q, want both values", got)
+	}
+	if len(resp.Cookies) != 2 || resp.Cookies[0].Name != "session" || !resp.Cookies[0].HttpOnly || resp.Cookies[1].MaxAge != 60 {
+		t.Errorf("unexpected cookies: %+v", resp.Cookies)
+	}
+	if resp.Size() != len(`{"id":1}`) || resp.PrettyBody() != "{\n    \"id\": 1\n}" {
+		t.Errorf("unexpected body: %d %q", resp.Size(), resp.PrettyBody())
+	}
+	if resp.Timing.Total <= 0 {
+		t.Errorf("Timing = %+v, want the timing of the request", resp.Timing)
+	}
+	if resp.Request == nil || resp.Request.URL.Path != "/items" {
+		t.Errorf("Request = %v, want the request sent", resp.Request)
+	}
+
+	raw := resp.Raw()
+	if !strings.HasPrefix(raw, "HTTP/1.1 201 Created\r\n") || !strings.Contains(raw, "X-Multi: a\r\nX-Multi: b\r\n") || !strings.HasSuffix(raw, "\r\n\r\n{\"id\":1}") {
+		t.Errorf("unexpected raw response:\n%s", raw)
+	}
+	if resp.HeaderSize() != len(raw)-resp.Size() {
+		t.Errorf("HeaderSize() = %d, want %d", resp.HeaderSize(), len(raw)-resp.Size())
+	}
+}
+
+func TestResponse_StatusLine(t *testing.T) {
+	tests := []struct {
+		resp Response
+		want string
+	}{
+		{Response{Proto: "HTTP/1.1", Status: "200 OK", StatusCode: 200}, "HTTP/1.1 200 OK\n"},
+		{Response{Proto: "HTTP/2.0", Status: "418 I'm a teapot", StatusCode: 418}, "HTTP/2.0 418 I'm a teapot\n"},
+		{Response{StatusCode: 404}, "HTTP 404 Not Found\n"},
+	}
+	for _, tt := range tests {
+		if got := tt.resp.StatusLine(); got != tt.want {
+			t.Errorf("StatusLine() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultMaxRedirects is the count of redirects followed when Settings do
+// not set one, the limit of net/http
+const defaultMaxRedirects = 10
+
+// Settings configure the client sending a request. The zero value follows
+// redirects, verifies certificates and has no timeout.
+type Settings struct {
+	// Timeout is the time limit of the request, 0 for none
+	Timeout time.Duration
+	// DisableRedirects returns redirect responses instead of following them
+	DisableRedirects bool
+	// MaxRedirects is the count of redirects followed, 0 for 10
+	MaxRedirects int
+	// Proxy is the URL of the HTTP or HTTPS proxy, empty for the proxy of the
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+	Proxy string
+	// NoProxy lists the hosts reached without Proxy, like NO_PROXY: host
+	// names matching their subdomains too, ".domain" suffixes, IP
+	// addresses, CIDR ranges, each with an optional port, or "*"
+	NoProxy string
+	// InsecureSkipVerify accepts any server certificate
+	InsecureSkipVerify bool
+	// MaxResponseSize is the size of the response body read into memory, 0
+	// for 2 MB. The rest is dropped unless KeepFullResponse is set.
+	MaxResponseSize int64
+	// KeepFullResponse streams the whole body of a response larger than
+	// MaxResponseSize to a temporary file, see Response.BodyFile
+	KeepFullResponse bool
+	// TLS configures the client certificates and CA certificates of hosts,
+	// the first matching a host applies
+	TLS []HostTLS
+}
+
+// DefaultSettings returns the settings of Client
+func DefaultSettings() Settings {
+	return Settings{Timeout: 10 * time.Second, MaxResponseSize: defaultMaxResponseSize}
+}
+
+// settingsKey is the request context key of the request's Settings
+type settingsKey struct{}
+
+// WithSettings returns ctx with the settings of the requests created with it
+// by NewRequest. Requests without settings are sent by Client.
+func WithSettings(ctx context.Context, s Settings) context.Context {
+	return context.WithValue(ctx, settingsKey{}, s)
+}
+
+// maxClients is the count of clients kept for settings
+const maxClients = 8
+
+// clients are the clients built for settings, kept to reuse their
+// connections. They are keyed by cacheKey, the least recently used first.
+var clients struct {
+	sync.Mutex
+	keys []string
+	m    map[string]*http.Client
+}
+
+// clientFor returns the client sending rq: the client of its settings or
+// Client
+func clientFor(rq *http.Request) (*http.Client, error) {
+	s, ok := rq.Context().Value(settingsKey{}).(Settings)
+	if !ok {
+		return Client, nil
+	}
+	return s.Client()
+}
+
+// bodyLimitFor returns the size of the body of the response to rq read into
+// memory and whether a larger body is kept in a file
+func bodyLimitFor(rq *http.Request) (limit int64, keep bool) {
+	s, _ := rq.Context().Value(settingsKey{}).(Settings)
+	if s.MaxResponseSize <= 0 {
+		return defaultMaxResponseSize, s.KeepFullResponse
+	}
+	return s.MaxResponseSize, s.KeepFullResponse
+}
+
+// Client returns the client sending requests with s. The last clients built
+// are kept, and built again when a file of the TLS settings is modified.
+func (s Settings) Client() (*http.Client, error) {
+	key, err := s.cacheKey()
+	if err != nil {
+		return nil, err
+	}
+	clients.Lock()
+	defer clients.Unlock()
+	if c := clients.m[key]; c != nil {
+		clients.keys = append(slices.DeleteFunc(clients.keys, func(k string) bool { return k == key }), key)
+		return c, nil
+	}
+
+	proxy, err := s.proxy()
+	if err != nil {
+		return nil, err
+	}
+	transport, err := newHostTransport(s, proxy)
+	if err != nil {
+		return nil, err
+	}
+	c := &http.Client{
+		Timeout:       s.Timeout,
+		Transport:     transport,
+		CheckRedirect: s.checkRedirect,
+	}
+	if clients.m == nil {
+		clients.m = make(map[string]*http.Client)
+	}
+	if len(clients.keys) == maxClients {
+		clients.m[clients.keys[0]].CloseIdleConnections()
+		delete(clients.m, clients.keys[0])
+		clients.keys = clients.keys[1:]
+	}
+	clients.m[key] = c
+	clients.keys = append(clients.keys, key)
+	return c, nil
+}
+
+// cacheKey returns the key of the client of s: the settings of the client
+// with the modification times of the files of the TLS settings
+func (s Settings) cacheKey() (string, error) {
+	// the body settings apply to reading the response, not to the client
+	s.MaxResponseSize, s.KeepFullResponse = 0, false
+	var modified []time.Time
+	for _, h := range s.TLS {
+		for _, name := range []string{h.CertFile, h.KeyFile, h.CAFile} {
+			var t time.Time
+			if info, err := os.Stat(name); err == nil {
+				t = info.ModTime()
+			}
+			modified = append(modified, t)
+		}
+	}
+	data, err := json.Marshal(struct {
+		Settings
+		Modified []time.Time
+	}{s, modified})
+	return string(data), err
+}
+
+func (s Settings) checkRedirect(rq *http.Request, via []*http.Request) error {
+	if s.DisableRedirects {
+		return http.ErrUseLastResponse
+	}
+	limit := s.MaxRedirects
+	if limit <= 0 {
+		limit = defaultMaxRedirects
+	}
+	if len(via) > limit {
+		return fmt.Errorf("stopped after %d redirects", limit)
+	}
+	return nil
+}
+
+// proxy returns the proxy function of the transport
+func (s Settings) proxy() (func(*http.Request) (*url.URL, error), error) {
+	if s.Proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	raw := s.Proxy
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	proxyURL, err := url.Parse(raw)
+	if err != nil || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q", s.Proxy)
+	}
+	return func(rq *http.Request) (*url.URL, error) {
+		if BypassProxy(s.NoProxy, rq.URL) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// BypassProxy reports whether u is reached without a proxy by the NoProxy
+// list noProxy, see Settings
+func BypassProxy(noProxy string, u *url.URL) bool {
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[u.Scheme]
+	}
+	ip := net.ParseIP(host)
+
+	for _, entry := range strings.FieldsFunc(strings.ToLower(noProxy), func(r rune) bool { return r == ',' || r == ' ' }) {
+		if entry == "*" {
+			return true
+		}
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		entryHost, entryPort, err := net.SplitHostPort(entry)
+		if err != nil {
+			var addrErr *net.AddrError
+			if !errors.As(err, &addrErr) {
+				continue
+			}
+			// no port
+			entryHost, entryPort = strings.Trim(entry, "[]"), ""
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+		if entryIP := net.ParseIP(entryHost); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		domain := strings.TrimPrefix(entryHost, ".")
+		if host == domain && !strings.HasPrefix(entryHost, ".") || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
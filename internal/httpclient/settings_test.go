@@ -0,0 +1,183 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sendWith sends a GET request to url with s
+func sendWith(t *testing.T, s Settings, url string) (*Response, error) {
+	t.Helper()
+	rq, err := NewRequest(WithSettings(context.Background(), s), "GET", url, "", "", nil)
+	if err != nil {
+		t.Fatalf("NewRequest error: %v", err)
+	}
+	return Send(rq)
+}
+
+func TestSettings_Redirects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// /3 redirects to /2, to /1, to /0 answering
+		switch r.URL.Path {
+		case "/0":
+			io.WriteString(w, "done")
+		case "/1":
+			http.Redirect(w, r, "/0", http.StatusFound)
+		case "/2":
+			http.Redirect(w, r, "/1", http.StatusFound)
+		case "/3":
+			http.Redirect(w, r, "/2", http.StatusFound)
+		}
+	}))
+	defer ts.Close()
+
+	if resp, err := sendWith(t, Settings{}, ts.URL+"/3"); err != nil || string(resp.Body) != "done" {
+		t.Errorf("redirects not followed: %v", err)
+	}
+	if resp, err := sendWith(t, Settings{MaxRedirects: 3}, ts.URL+"/3"); err != nil || string(resp.Body) != "done" {
+		t.Errorf("3 redirects not followed with MaxRedirects 3: %v", err)
+	}
+	if _, err := sendWith(t, Settings{MaxRedirects: 2}, ts.URL+"/3"); err == nil || !strings.Contains(err.Error(), "stopped after 2 redirects") {
+		t.Errorf("3 redirects followed with MaxRedirects 2: %v", err)
+	}
+	resp, err := sendWith(t, Settings{DisableRedirects: true}, ts.URL+"/3")
+	if err != nil || resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/2" {
+		t.Errorf("redirect followed with DisableRedirects: %v", err)
+	}
+}
+
+func TestSettings_Timeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+
+	if _, err := sendWith(t, Settings{Timeout: 50 * time.Millisecond}, ts.URL); err == nil {
+		t.Error("request not stopped by the timeout")
+	}
+}
+
+func TestSettings_Proxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "direct")
+	}))
+	defer target.Close()
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a proxy receives the absolute URL of the target
+		io.WriteString(w, "proxied "+r.URL.String())
+	}))
+	defer proxy.Close()
+
+	resp, err := sendWith(t, Settings{Proxy: proxy.URL}, target.URL+"/a")
+	if err != nil || string(resp.Body) != "proxied "+target.URL+"/a" {
+		t.Errorf("request not sent through the proxy: %q, %v", resp.Body, err)
+	}
+	resp, err = sendWith(t, Settings{Proxy: strings.TrimPrefix(proxy.URL, "http://"), NoProxy: "example.com, 127.0.0.1"}, target.URL)
+	if err != nil || string(resp.Body) != "direct" {
+		t.Errorf("request to a NoProxy host sent through the proxy: %q, %v", resp.Body, err)
+	}
+	if _, err := sendWith(t, Settings{Proxy: "http://"}, target.URL); err == nil {
+		t.Error("request sent with an invalid proxy URL")
+	}
+}
+
+func TestSettings_InsecureSkipVerify(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer ts.Close()
+
+	if _, err := sendWith(t, Settings{}, ts.URL); err == nil {
+		t.Error("self-signed certificate accepted")
+	}
+	if resp, err := sendWith(t, Settings{InsecureSkipVerify: true}, ts.URL); err != nil || string(resp.Body) != "ok" {
+		t.Errorf("request with InsecureSkipVerify failed: %v", err)
+	}
+}
+
+func TestSettings_Client(t *testing.T) {
+	first, err := Settings{Timeout: time.Hour}.Client()
+	if err != nil {
+		t.Fatalf("Client error: %v", err)
+	}
+	if c, _ := (Settings{Timeout: time.Hour, MaxResponseSize: 10}).Client(); c != first {
+		t.Error("the body settings built another client")
+	}
+
+	// the least recently used clients are dropped
+	oldest, _ := Settings{Timeout: time.Hour + 1}.Client()
+	for i := 2; i < maxClients; i++ {
+		Settings{Timeout: time.Hour + time.Duration(i)}.Client()
+	}
+	if c, _ := (Settings{Timeout: time.Hour}).Client(); c != first {
+		t.Error("a recently used client was dropped")
+	}
+	Settings{Timeout: 2 * time.Hour}.Client()
+	if c, _ := (Settings{Timeout: time.Hour + 1}).Client(); c == oldest {
+		t.Error("the least recently used client was kept")
+	}
+	if c, _ := (Settings{Timeout: time.Hour}).Client(); c != first {
+		t.Error("a recently used client was dropped")
+	}
+
+	// a client is built again when a file of its TLS settings is modified
+	caFile := writeTestFile(t, "ca.pem", []byte(testCAPEM))
+	s := Settings{TLS: []HostTLS{{Host: "*", CAFile: caFile}}}
+	c, err := s.Client()
+	if err != nil {
+		t.Fatalf("Client error: %v", err)
+	}
+	if again, _ := s.Client(); again != c {
+		t.Error("client built again without a modified file")
+	}
+	modified := time.Now().Add(time.Minute)
+	if err := os.Chtimes(caFile, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := s.Client(); again == c {
+		t.Error("client kept with a modified CA file")
+	}
+}
+
+func TestBypassProxy(t *testing.T) {
+	tests := []struct {
+		noProxy string
+		url     string
+		want    bool
+	}{
+		{"", "http://example.com", false},
+		{"*", "http://example.com", true},
+		{"example.com", "http://example.com", true},
+		{"example.com", "http://api.example.com", true},
+		{"example.com", "http://notexample.com", false},
+		{".example.com", "http://example.com", false},
+		{".example.com", "https://api.example.com", true},
+		{"EXAMPLE.com:8080", "http://example.com:8080", true},
+		{"example.com:8080", "http://example.com", false},
+		{"example.com:443", "https://example.com", true},
+		{"10.0.0.0/8, localhost", "http://10.1.2.3:9000", true},
+		{"10.0.0.0/8", "http://11.1.2.3", false},
+		{"192.168.1.1", "http://192.168.1.1", true},
+		{"::1", "http://[::1]:8080", true},
+		{"[::1]:8080", "http://[::1]:8080", true},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := BypassProxy(tt.noProxy, u); got != tt.want {
+			t.Errorf("BypassProxy(%q, %q) = %v, want %v", tt.noProxy, tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Phase is a phase of a request, as offsets from the start of the request
+type Phase struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Duration returns how long the phase took
+func (p Phase) Duration() time.Duration {
+	return p.End - p.Start
+}
+
+// Timing is the timing breakdown of a request sent by Send. The
+// phases of a reused connection are empty.
+type Timing struct {
+	// Start is when the request was sent, the origin of the phases
+	Start        time.Time
+	DNS          Phase
+	Connect      Phase
+	TLSHandshake Phase
+	// Wait is from the request being written to the first response byte
+	Wait     Phase
+	Download Phase
+	Total    time.Duration
+	// ReusedConn is set when the request was sent on a kept-alive connection
+	ReusedConn bool
+}
+
+// timingKey is the request context key of the request's timingRecorder
+type timingKey struct{}
+
+// timingRecorder collects the Timing of a request from its httptrace hooks,
+// which may be called concurrently
+type timingRecorder struct {
+	mu     sync.Mutex
+	start  time.Time
+	timing Timing
+}
+
+// RequestTiming returns the timing of the last Send of rq. It reports
+// false for a request not created by NewRequest or not sent yet.
+func RequestTiming(rq *http.Request) (Timing, bool) {
+	r, _ := rq.Context().Value(timingKey{}).(*timingRecorder)
+	if r == nil {
+		return Timing{}, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.timing, !r.start.IsZero()
+}
+
+// trace returns rq with hooks recording its timing, starting now
+func (r *timingRecorder) trace(rq *http.Request) *http.Request {
+	r.mu.Lock()
+	r.start = time.Now()
+	r.timing = Timing{Start: r.start}
+	r.mu.Unlock()
+
+	record := func(f func(now time.Duration)) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		f(time.Since(r.start))
+	}
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { record(func(now time.Duration) { r.timing.DNS.Start = now }) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { record(func(now time.Duration) { r.timing.DNS.End = now }) },
+		ConnectStart: func(string, string) {
+			record(func(now time.Duration) {
+				// several addresses may be dialed, the phase spans all of them
+				if r.timing.Connect.Start == 0 {
+					r.timing.Connect.Start = now
+				}
+			})
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				record(func(now time.Duration) { r.timing.Connect.End = now })
+			}
+		},
+		TLSHandshakeStart: func() { record(func(now time.Duration) { r.timing.TLSHandshake.Start = now }) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			record(func(now time.Duration) { r.timing.TLSHandshake.End = now })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			record(func(time.Duration) { r.timing.ReusedConn = info.Reused })
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			record(func(now time.Duration) { r.timing.Wait.Start = now })
+		},
+		GotFirstResponseByte: func() {
+			record(func(now time.Duration) {
+				r.timing.Wait.End = now
+				r.timing.Download.Start = now
+			})
+		},
+	}
+	return rq.WithContext(httptrace.WithClientTrace(rq.Context(), trace))
+}
+
+// done ends the download and the request, unless they already ended
+func (r *timingRecorder) done() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.timing.Total != 0 {
+		return
+	}
+	now := time.Since(r.start)
+	if r.timing.Download.Start == 0 {
+		r.timing.Download.Start = now
+	}
+	r.timing.Download.End = now
+	r.timing.Total = now
+}
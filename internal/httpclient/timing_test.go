@@ -0,0 +1,56 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestTiming(t *testing.T) {
+	const wait = 30 * time.Millisecond
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(wait)
+		io.WriteString(w, "ok")
+	}))
+	defer ts.Close()
+
+	rq, err := NewRequest(context.Background(), "GET", ts.URL, "", "", nil)
+	if err != nil {
+		t.Fatalf("NewRequest error: %v", err)
+	}
+	if _, ok := RequestTiming(rq); ok {
+		t.Errorf("RequestTiming() of a request not sent reports a timing")
+	}
+	if _, _, _, err := SendRequest(rq); err != nil {
+		t.Fatalf("SendRequest error: %v", err)
+	}
+
+	timing, ok := RequestTiming(rq)
+	if !ok {
+		t.Fatalf("RequestTiming() reports no timing")
+	}
+	if timing.ReusedConn || timing.Connect.End == 0 || timing.Connect.End < timing.Connect.Start {
+		t.Errorf("unexpected connect phase of a new connection: %+v", timing)
+	}
+	if timing.Wait.Duration() < wait || timing.Wait.Start < timing.Connect.End {
+		t.Errorf("Wait = %+v, want at least %s after the connection", timing.Wait, wait)
+	}
+	if timing.Download.Start != timing.Wait.End || timing.Total < timing.Download.End {
+		t.Errorf("unexpected download phase or total: %+v", timing)
+	}
+	if timing.TLSHandshake.Duration() != 0 {
+		t.Errorf("TLSHandshake = %+v for a plain HTTP request", timing.TLSHandshake)
+	}
+
+	// a request sent again on the kept-alive connection has no connect phase
+	rq, _ = NewRequest(context.Background(), "GET", ts.URL, "", "", nil)
+	if _, _, _, err := SendRequest(rq); err != nil {
+		t.Fatalf("SendRequest error: %v", err)
+	}
+	if timing, _ := RequestTiming(rq); !timing.ReusedConn || timing.Connect.Duration() != 0 {
+		t.Errorf("timing of a reused connection = %+v", timing)
+	}
+}
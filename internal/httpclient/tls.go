@@ -0,0 +1,171 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// HostTLS configures the TLS connections to the hosts matching Host: the
+// client certificate presented and the CA certificates trusted besides the
+// system ones
+type HostTLS struct {
+	// Host is a host name, "*.domain" for the subdomains of domain, or "*"
+	// for any host, each with an optional port
+	Host string `json:"host"`
+	// CertFile is the client certificate: a PEM file, holding the key too
+	// without KeyFile, or a PKCS #12 (.p12, .pfx) file
+	CertFile string `json:"certFile,omitempty"`
+	// KeyFile is the PEM file of the key of CertFile
+	KeyFile string `json:"keyFile,omitempty"`
+	// Password decrypts a PKCS #12 CertFile
+	Password string `json:"password,omitempty"`
+	// CAFile is a PEM bundle of the CA certificates trusted
+	CAFile string `json:"caFile,omitempty"`
+}
+
+// Matches reports whether the host of u matches Host
+func (h HostTLS) Matches(u *url.URL) bool {
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[u.Scheme]
+	}
+
+	pattern := strings.ToLower(strings.TrimSpace(h.Host))
+	patternHost, patternPort, err := net.SplitHostPort(pattern)
+	if err != nil {
+		patternHost, patternPort = strings.Trim(pattern, "[]"), ""
+	}
+	if patternPort != "" && patternPort != port {
+		return false
+	}
+	switch {
+	case patternHost == "*":
+		return true
+	case strings.HasPrefix(patternHost, "*."):
+		return strings.HasSuffix(host, patternHost[1:])
+	}
+	return host == patternHost
+}
+
+// config returns the TLS configuration of the hosts
+func (h HostTLS) config(insecureSkipVerify bool) (*tls.Config, error) {
+	c := &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+	if h.CertFile != "" {
+		cert, err := h.certificate()
+		if err != nil {
+			return nil, err
+		}
+		c.Certificates = []tls.Certificate{cert}
+	}
+	if h.CAFile != "" {
+		pem, err := os.ReadFile(h.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate in CA file %s", h.CAFile)
+		}
+		c.RootCAs = pool
+	}
+	return c, nil
+}
+
+// certificate loads the client certificate of CertFile and KeyFile
+func (h HostTLS) certificate() (tls.Certificate, error) {
+	if h.KeyFile != "" {
+		return tls.LoadX509KeyPair(h.CertFile, h.KeyFile)
+	}
+	data, err := os.ReadFile(h.CertFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	var cert tls.Certificate
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		cert, err = tls.X509KeyPair(data, data)
+	} else {
+		cert, err = decodePKCS12(data, h.Password)
+	}
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("client certificate %s: %w", h.CertFile, err)
+	}
+	return cert, nil
+}
+
+// decodePKCS12 returns the key and certificate chain of a PKCS #12 file
+func decodePKCS12(data []byte, password string) (tls.Certificate, error) {
+	key, leaf, chain, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	cert := tls.Certificate{PrivateKey: key, Leaf: leaf, Certificate: [][]byte{leaf.Raw}}
+	for _, c := range chain {
+		cert.Certificate = append(cert.Certificate, c.Raw)
+	}
+	return cert, nil
+}
+
+// hostTransport sends the requests to the hosts of TLS settings with their
+// transports, the first matching, and the other requests with fallback
+type hostTransport struct {
+	hosts      []HostTLS
+	transports []*http.Transport
+	fallback   *http.Transport
+}
+
+// newHostTransport returns the transport of s with proxy
+func newHostTransport(s Settings, proxy func(*http.Request) (*url.URL, error)) (http.RoundTripper, error) {
+	newTransport := func(c *tls.Config) *http.Transport {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = proxy
+		t.TLSClientConfig = c
+		return t
+	}
+	fallback := newTransport(&tls.Config{InsecureSkipVerify: s.InsecureSkipVerify})
+	if len(s.TLS) == 0 {
+		return fallback, nil
+	}
+
+	t := &hostTransport{hosts: s.TLS, fallback: fallback}
+	for _, h := range s.TLS {
+		if strings.TrimSpace(h.Host) == "" {
+			return nil, errors.New("TLS settings without host")
+		}
+		c, err := h.config(s.InsecureSkipVerify)
+		if err != nil {
+			return nil, fmt.Errorf("TLS settings of %s: %w", h.Host, err)
+		}
+		t.transports = append(t.transports, newTransport(c))
+	}
+	return t, nil
+}
+
+func (t *hostTransport) RoundTrip(rq *http.Request) (*http.Response, error) {
+	for i, h := range t.hosts {
+		if h.Matches(rq.URL) {
+			return t.transports[i].RoundTrip(rq)
+		}
+	}
+	return t.fallback.RoundTrip(rq)
+}
+
+// CloseIdleConnections closes the idle connections of all the transports
+func (t *hostTransport) CloseIdleConnections() {
+	for _, transport := range t.transports {
+		transport.CloseIdleConnections()
+	}
+	t.fallback.CloseIdleConnections()
+}
@@ -0,0 +1,82 @@
+package httpclient
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Characters allowed unescaped in a path segment and in a query key or
+// value besides the unreserved ones (RFC 3986). "&" and "=" delimit the
+// query parameters and are escaped in them.
+const (
+	pathAllowed  = "!$&'()*+,;=:@"
+	queryAllowed = "!$'()*+,;:@/?"
+)
+
+// EncodeURL returns the URL raw with its :name path segments replaced by the
+// values of pathVariables and the path and query percent-encoded. Escapes
+// already in raw are kept, so an encoded URL is returned as it is.
+func EncodeURL(raw string, pathVariables map[string]string) string {
+	rest, hash, hasHash := strings.Cut(raw, "#")
+	rest, query, hasQuery := strings.Cut(rest, "?")
+
+	// The path starts at the first "/" after the scheme and host
+	var prefix string
+	if scheme, after, ok := strings.Cut(rest, "://"); ok {
+		prefix, rest = scheme+"://", after
+	}
+	host, path, hasPath := strings.Cut(rest, "/")
+
+	var b strings.Builder
+	b.WriteString(prefix + host)
+	if hasPath {
+		for _, segment := range strings.Split(path, "/") {
+			if name, ok := strings.CutPrefix(segment, ":"); ok {
+				if value, ok := pathVariables[name]; ok {
+					segment = value
+				}
+			}
+			b.WriteString("/" + escape(segment, pathAllowed))
+		}
+	}
+	if hasQuery {
+		b.WriteString("?")
+		for i, pair := range strings.Split(query, "&") {
+			if i > 0 {
+				b.WriteString("&")
+			}
+			key, value, hasValue := strings.Cut(pair, "=")
+			b.WriteString(escape(key, queryAllowed))
+			if hasValue {
+				b.WriteString("=" + escape(value, queryAllowed+"="))
+			}
+		}
+	}
+	if hasHash {
+		b.WriteString("#" + hash)
+	}
+	return b.String()
+}
+
+// escape percent-encodes the bytes of s that are neither unreserved nor in
+// allowed, keeping valid escapes
+func escape(s, allowed string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			b.WriteByte(c)
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '.', c == '_', c == '~', strings.IndexByte(allowed, c) >= 0:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
@@ -0,0 +1,27 @@
+package httpclient
+
+import "testing"
+
+func TestEncodeURL(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		vars map[string]string
+		want string
+	}{
+		{"plain", "https://example.com/a/b?x=1&y=2", nil, "https://example.com/a/b?x=1&y=2"},
+		{"query values", "https://example.com/search?q=a b&name=Jürgen&sum=1+1=2", nil, "https://example.com/search?q=a%20b&name=J%C3%BCrgen&sum=1+1=2"},
+		{"escapes kept", "https://example.com/search?q=a%20b&r=100%", nil, "https://example.com/search?q=a%20b&r=100%25"},
+		{"path variables", "https://example.com/users/:id/files/:name", map[string]string{"id": "42", "name": "a b/c.txt"}, "https://example.com/users/42/files/a%20b%2Fc.txt"},
+		{"unknown path variable", "https://example.com/users/:id", nil, "https://example.com/users/:id"},
+		{"hash", "https://example.com/a b?k#top", nil, "https://example.com/a%20b?k#top"},
+		{"no scheme", "example.com:8080/v1?a=\"x\"", nil, "example.com:8080/v1?a=%22x%22"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EncodeURL(tt.raw, tt.vars); got != tt.want {
+				t.Errorf("EncodeURL(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
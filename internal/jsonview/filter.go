@@ -0,0 +1,724 @@
+package jsonview
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Query is a compiled filter of a JSON document: a JSONPath starting with
+// "$", such as $.items[?(@.price < 10)].id, or otherwise a jq-style
+// pipeline such as .items[] | select(.price < 10) | .id
+//
+// JSONPath supports members (.name, ['name'], ."name"), indexes and unions ([0],
+// [-1], [0,2]), slices ([1:3]), wildcards ([*], .*), recursive descent
+// (..name) and filters ([?(@.a == 1 && @.b)]). jq supports the same paths
+// written from ".", the iteration .[], pipes and select, length and keys.
+type Query struct {
+	steps    []step
+	jsonPath bool
+}
+
+// step maps the nodes selected so far to the next selection
+type step func([]*Node) []*Node
+
+// selector selects nodes relative to one node
+type selector func(*Node) []*Node
+
+// condition reports whether a node passes a filter
+type condition func(*Node) bool
+
+// Filter evaluates the query expr against the JSON document data and
+// returns the result formatted by Query.Format
+func Filter(data []byte, expr string) (string, error) {
+	root, err := Parse(data)
+	if err != nil {
+		return "", err
+	}
+	q, err := Compile(expr)
+	if err != nil {
+		return "", err
+	}
+	return q.Format(q.Eval(root)), nil
+}
+
+// Compile parses the query expr
+func Compile(expr string) (*Query, error) {
+	p := &queryParser{s: strings.TrimSpace(expr)}
+	q := &Query{}
+	if p.consume("$") {
+		q.jsonPath, p.jsonPath = true, true
+		steps, err := p.path()
+		if err != nil {
+			return nil, err
+		}
+		q.steps = steps
+	} else {
+		for {
+			steps, err := p.term()
+			if err != nil {
+				return nil, err
+			}
+			q.steps = append(q.steps, steps...)
+			p.space()
+			if !p.consume("|") {
+				break
+			}
+		}
+	}
+	p.space()
+	if p.pos < len(p.s) {
+		return nil, p.errorf("unexpected %q", p.s[p.pos:])
+	}
+	return q, nil
+}
+
+// Eval returns the nodes the query selects from root, in document order
+// for each step
+func (q *Query) Eval(root *Node) []*Node {
+	nodes := []*Node{root}
+	for _, s := range q.steps {
+		nodes = s(nodes)
+	}
+	return nodes
+}
+
+// Format returns nodes as indented JSON: an array of the values for a
+// JSONPath, and the values one after the other, like jq, otherwise
+func (q *Query) Format(nodes []*Node) string {
+	if !q.jsonPath {
+		values := make([]string, len(nodes))
+		for i, n := range nodes {
+			values[i] = n.Value()
+		}
+		return strings.Join(values, "\n")
+	}
+	raws := make([][]byte, len(nodes))
+	for i, n := range nodes {
+		raws[i] = n.Raw
+	}
+	array := append(append([]byte("["), bytes.Join(raws, []byte(","))...), ']')
+	var b bytes.Buffer
+	if err := json.Indent(&b, array, "", "    "); err != nil {
+		return string(array)
+	}
+	return b.String()
+}
+
+type queryParser struct {
+	s   string
+	pos int
+	// jsonPath is set for JSONPath queries, where conditions start from "@"
+	// and use && and ||, while jq conditions start from "." and use and, or
+	jsonPath bool
+}
+
+func (p *queryParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid filter at position %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *queryParser) space() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *queryParser) peek(s string) bool {
+	return strings.HasPrefix(p.s[p.pos:], s)
+}
+
+func (p *queryParser) consume(s string) bool {
+	if p.peek(s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// consumeWord consumes the keyword w when it is not the start of a longer name
+func (p *queryParser) consumeWord(w string) bool {
+	end := p.pos + len(w)
+	if !p.peek(w) || end < len(p.s) && isNameByte(p.s[end]) {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+func (p *queryParser) expect(s string) error {
+	p.space()
+	if !p.consume(s) {
+		return p.errorf("expected %q", s)
+	}
+	return nil
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// name parses a member name written with dot notation
+func (p *queryParser) name() string {
+	start := p.pos
+	for p.pos < len(p.s) && isNameByte(p.s[p.pos]) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// term parses a jq term: a path, select(condition), length or keys
+func (p *queryParser) term() ([]step, error) {
+	p.space()
+	switch {
+	case p.consumeWord("select"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		cond, err := p.condition()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return []step{func(nodes []*Node) []*Node {
+			return slices.DeleteFunc(slices.Clone(nodes), func(n *Node) bool { return !cond(n) })
+		}}, nil
+	case p.consumeWord("length"):
+		return []step{mapNodes(length)}, nil
+	case p.consumeWord("keys"):
+		return []step{mapNodes(keys)}, nil
+	case p.peek("."):
+		return p.path()
+	}
+	return nil, p.errorf("expected a path, select, length or keys")
+}
+
+// path parses the steps of a path after its start, "$", "@" or nothing in
+// jq, where a path starts from ".", e.g. ".items[0].id"
+func (p *queryParser) path() ([]step, error) {
+	var steps []step
+	for p.pos < len(p.s) {
+		switch {
+		case p.consume(".."):
+			steps = append(steps, descendants)
+			sel, err := p.dotSelector()
+			if err != nil {
+				return nil, err
+			}
+			if sel != nil {
+				steps = append(steps, each(sel))
+			} else if p.jsonPath {
+				return nil, p.errorf("expected a member name, * or [ after ..")
+			}
+		case p.consume("."):
+			sel, err := p.dotSelector()
+			if err != nil {
+				return nil, err
+			}
+			if sel != nil {
+				steps = append(steps, each(sel))
+			} else if p.jsonPath || len(steps) > 0 {
+				// a lone "." is the identity in jq, a path of its own
+				return nil, p.errorf("expected a member name, * or [ after .")
+			}
+		case p.peek("["):
+			sel, err := p.bracket()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, each(sel))
+		default:
+			return steps, nil
+		}
+	}
+	return steps, nil
+}
+
+// dotSelector parses what follows a dot: a member name, quoted or not, * or
+// a bracket. It returns nil when there is none of them.
+func (p *queryParser) dotSelector() (selector, error) {
+	switch {
+	case p.consume("*"):
+		return children, nil
+	case p.peek("["):
+		return p.bracket()
+	case p.peek(`"`):
+		name, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		return member(name), nil
+	}
+	if name := p.name(); name != "" {
+		return member(name), nil
+	}
+	return nil, nil
+}
+
+// bracket parses a bracket: [] or [*], a filter [?(condition)] or a union
+// of member names, indexes and slices, e.g. ['a','b'], [0,-1] or [1:3]
+func (p *queryParser) bracket() (selector, error) {
+	p.consume("[")
+	p.space()
+	switch {
+	case p.consume("]"):
+		return children, nil
+	case p.consume("*"):
+		return children, p.expect("]")
+	case p.consume("?"):
+		p.space()
+		parens := p.consume("(")
+		cond, err := p.condition()
+		if err != nil {
+			return nil, err
+		}
+		if parens {
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		}
+		return func(n *Node) []*Node {
+			var found []*Node
+			for _, child := range n.Children {
+				if cond(child) {
+					found = append(found, child)
+				}
+			}
+			return found
+		}, p.expect("]")
+	}
+
+	var union []selector
+	for {
+		p.space()
+		sel, err := p.bracketEntry()
+		if err != nil {
+			return nil, err
+		}
+		union = append(union, sel)
+		p.space()
+		if p.consume("]") {
+			break
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected \",\" or \"]\"")
+		}
+	}
+	if len(union) == 1 {
+		return union[0], nil
+	}
+	return func(n *Node) []*Node {
+		var found []*Node
+		for _, sel := range union {
+			found = append(found, sel(n)...)
+		}
+		return found
+	}, nil
+}
+
+// bracketEntry parses a quoted member name, an index or a slice
+func (p *queryParser) bracketEntry() (selector, error) {
+	if p.peek("'") || p.peek(`"`) {
+		name, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		return member(name), nil
+	}
+	start, hasStart, err := p.integer()
+	if err != nil {
+		return nil, err
+	}
+	p.space()
+	if !p.consume(":") {
+		if !hasStart {
+			return nil, p.errorf("expected a member name, an index or a slice")
+		}
+		return index(start), nil
+	}
+	p.space()
+	end, hasEnd, err := p.integer()
+	if err != nil {
+		return nil, err
+	}
+	return slice(start, end, hasStart, hasEnd), nil
+}
+
+// integer parses an optional integer, reporting whether there was one
+func (p *queryParser) integer() (int, bool, error) {
+	start := p.pos
+	p.consume("-")
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, false, nil
+	}
+	i, err := strconv.Atoi(p.s[start:p.pos])
+	if err != nil {
+		text := p.s[start:p.pos]
+		p.pos = start
+		return 0, false, p.errorf("invalid index %q", text)
+	}
+	return i, true, nil
+}
+
+// quoted parses a string in single or double quotes, with backslash escapes
+func (p *queryParser) quoted() (string, error) {
+	quote := p.s[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c == '\\' && p.pos < len(p.s):
+			// a JSON escape in double quotes, the next character otherwise
+			if quote == '"' {
+				end := p.pos + 1
+				if p.s[p.pos] == 'u' {
+					end = min(p.pos+5, len(p.s))
+				}
+				var s string
+				if err := json.Unmarshal([]byte(`"\`+p.s[p.pos:end]+`"`), &s); err == nil {
+					b.WriteString(s)
+					p.pos = end
+					continue
+				}
+			}
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// condition parses conditions joined by || in JSONPath or "or" in jq
+func (p *queryParser) condition() (condition, error) {
+	left, err := p.conjunction()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.space()
+		if !(p.jsonPath && p.consume("||") || !p.jsonPath && p.consumeWord("or")) {
+			return left, nil
+		}
+		right, err := p.conjunction()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(n *Node) bool { return l(n) || right(n) }
+	}
+}
+
+// conjunction parses comparisons joined by && in JSONPath or "and" in jq
+func (p *queryParser) conjunction() (condition, error) {
+	left, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.space()
+		if !(p.jsonPath && p.consume("&&") || !p.jsonPath && p.consumeWord("and")) {
+			return left, nil
+		}
+		right, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(n *Node) bool { return l(n) && right(n) }
+	}
+}
+
+// comparators are the comparison operators, the longer ones first
+var comparators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// comparison parses a path relative to the current node, compared with a
+// literal, or alone. A path alone passes when it exists in JSONPath, and
+// when its value is neither false nor null in jq.
+func (p *queryParser) comparison() (condition, error) {
+	p.space()
+	current := "."
+	if p.jsonPath {
+		current = "@"
+	}
+	if !p.peek(current) {
+		return nil, p.errorf("expected a path starting with %q", current)
+	}
+	if p.jsonPath {
+		p.consume("@")
+	}
+	steps, err := p.path()
+	if err != nil {
+		return nil, err
+	}
+	operand := &Query{steps: steps}
+
+	p.space()
+	for _, op := range comparators {
+		if !p.consume(op) {
+			continue
+		}
+		p.space()
+		literal, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		return func(n *Node) bool {
+			for _, value := range operand.Eval(n) {
+				if compare(value, op, literal) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+	jsonPath := p.jsonPath
+	return func(n *Node) bool {
+		for _, value := range operand.Eval(n) {
+			if jsonPath || !(value.Kind == Null || value.Kind == Bool && string(value.Raw) == "false") {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// literal parses a string, number, true, false or null
+func (p *queryParser) literal() (*Node, error) {
+	var raw []byte
+	switch {
+	case p.peek("'") || p.peek(`"`):
+		s, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		raw, _ = json.Marshal(s)
+	case p.consumeWord("true"):
+		raw = []byte("true")
+	case p.consumeWord("false"):
+		raw = []byte("false")
+	case p.consumeWord("null"):
+		raw = []byte("null")
+	default:
+		start := p.pos
+		for p.pos < len(p.s) && strings.IndexByte("+-.0123456789eE", p.s[p.pos]) >= 0 {
+			p.pos++
+		}
+		raw = []byte(p.s[start:p.pos])
+		if len(raw) == 0 || !json.Valid(raw) {
+			p.pos = start
+			return nil, p.errorf("expected a string, number, true, false or null")
+		}
+	}
+	return newValue(raw), nil
+}
+
+// newValue returns the node of a JSON value that is not part of a document
+func newValue(raw []byte) *Node {
+	n, err := Parse(raw)
+	if err != nil {
+		return &Node{Index: -1, Path: "$", Kind: Null, Raw: json.RawMessage("null")}
+	}
+	return n
+}
+
+// compare compares numbers by value and strings by text, other values are
+// only equal when their JSON is
+func compare(a *Node, op string, b *Node) bool {
+	c, ok := 0, false
+	switch {
+	case a.Kind == Number && b.Kind == Number:
+		x, errX := strconv.ParseFloat(string(a.Raw), 64)
+		y, errY := strconv.ParseFloat(string(b.Raw), 64)
+		c, ok = cmpFloat(x, y), errX == nil && errY == nil
+	case a.Kind == String && b.Kind == String:
+		c, ok = strings.Compare(a.Text(), b.Text()), true
+	}
+	if !ok {
+		var x, y bytes.Buffer
+		equal := json.Compact(&x, a.Raw) == nil && json.Compact(&y, b.Raw) == nil && bytes.Equal(x.Bytes(), y.Bytes())
+		switch op {
+		case "==":
+			return equal
+		case "!=":
+			return !equal
+		}
+		return false
+	}
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+func cmpFloat(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// each applies sel to every node selected so far
+func each(sel selector) step {
+	return func(nodes []*Node) []*Node {
+		var found []*Node
+		for _, n := range nodes {
+			found = append(found, sel(n)...)
+		}
+		return found
+	}
+}
+
+// mapNodes replaces every node with the result of f, dropping nils
+func mapNodes(f func(*Node) *Node) step {
+	return func(nodes []*Node) []*Node {
+		var found []*Node
+		for _, n := range nodes {
+			if v := f(n); v != nil {
+				found = append(found, v)
+			}
+		}
+		return found
+	}
+}
+
+// descendants selects every node and its descendants
+func descendants(nodes []*Node) []*Node {
+	var found []*Node
+	for _, n := range nodes {
+		n.Walk(func(d *Node) bool {
+			found = append(found, d)
+			return true
+		})
+	}
+	return found
+}
+
+func children(n *Node) []*Node {
+	return n.Children
+}
+
+func member(name string) selector {
+	return func(n *Node) []*Node {
+		if n.Kind != Object {
+			return nil
+		}
+		for _, child := range n.Children {
+			if child.Key == name {
+				return []*Node{child}
+			}
+		}
+		return nil
+	}
+}
+
+// index selects an array item, counting from the end when i is negative
+func index(i int) selector {
+	return func(n *Node) []*Node {
+		if n.Kind != Array {
+			return nil
+		}
+		j := i
+		if j < 0 {
+			j += len(n.Children)
+		}
+		if j < 0 || j >= len(n.Children) {
+			return nil
+		}
+		return []*Node{n.Children[j]}
+	}
+}
+
+// slice selects the array items from start up to end, both counting from the
+// end when negative and defaulting to the whole array
+func slice(start, end int, hasStart, hasEnd bool) selector {
+	return func(n *Node) []*Node {
+		if n.Kind != Array {
+			return nil
+		}
+		size := len(n.Children)
+		bound := func(i, def int, ok bool) int {
+			if !ok {
+				return def
+			}
+			if i < 0 {
+				i += size
+			}
+			return min(max(i, 0), size)
+		}
+		from, to := bound(start, 0, hasStart), bound(end, size, hasEnd)
+		if from >= to {
+			return nil
+		}
+		return n.Children[from:to]
+	}
+}
+
+// length returns the count of items or members, the count of characters of
+// a string, the absolute value of a number and 0 for null, like jq
+func length(n *Node) *Node {
+	var size string
+	switch n.Kind {
+	case Array, Object:
+		size = strconv.Itoa(len(n.Children))
+	case String:
+		size = strconv.Itoa(utf8.RuneCountInString(n.Text()))
+	case Number:
+		size = strings.TrimPrefix(string(n.Raw), "-")
+	case Null:
+		size = "0"
+	default:
+		return nil
+	}
+	return newValue([]byte(size))
+}
+
+// keys returns the sorted member names of an object or the indexes of an
+// array, like jq
+func keys(n *Node) *Node {
+	var list any
+	switch n.Kind {
+	case Object:
+		names := make([]string, len(n.Children))
+		for i, child := range n.Children {
+			names[i] = child.Key
+		}
+		slices.Sort(names)
+		list = names
+	case Array:
+		indexes := make([]int, len(n.Children))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		list = indexes
+	default:
+		return nil
+	}
+	raw, _ := json.Marshal(list)
+	return newValue(raw)
+}
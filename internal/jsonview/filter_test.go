@@ -0,0 +1,80 @@
+package jsonview
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		// JSONPath
+		{"$", "[" + compact(testDocument) + "]"},
+		{"$.count", "[2]"},
+		{"$.store.book[*].title", `["Sayings of the Century","Moby Dick"]`},
+		{"$.store.book[-1].title", `["Moby Dick"]`},
+		{"$.store.book[0:1].price", `[8.95]`},
+		{"$.store.book[:5]['in stock']", `[true]`},
+		{"$.store.book[0,1,7].price", `[8.95,8.99]`},
+		{`$.store['owner\'s name']`, `["Ann"]`},
+		{`$.store["owner's name"]`, `["Ann"]`},
+		{"$..price", `[8.95,8.99]`},
+		{"$..book[1].isbn", `[null]`},
+		{"$.store.*", `[` + compact(`[
+			{"title": "Sayings of the Century", "price": 8.95, "in stock": true},
+			{"title": "Moby Dick", "price": 8.99, "isbn": null}]`) + `,"Ann"]`},
+		{"$.store.book[?(@.price < 8.98)].title", `["Sayings of the Century"]`},
+		{"$.store.book[?(@.price >= 8.95 && @.title != 'Moby Dick')].price", `[8.95]`},
+		{"$.store.book[?(@.isbn || @.price == 8.95)].title", `["Sayings of the Century","Moby Dick"]`},
+		{"$.store.book[?@.title == \"Moby Dick\"].price", `[8.99]`},
+		{"$.missing", `[]`},
+
+		// jq
+		{".count", "2"},
+		{".store.book[] | .title", `"Sayings of the Century"` + "\n" + `"Moby Dick"`},
+		{".store.book[1].price", "8.99"},
+		{`.store["owner's name"]`, `"Ann"`},
+		{".store.book | length", "2"},
+		{".store.book[0] | keys", "[\n    \"in stock\",\n    \"price\",\n    \"title\"\n]"},
+		{".store.book[] | select(.price > 8.96) | .title", `"Moby Dick"`},
+		{".store.book[] | select(.isbn) | .title", ``},
+		{".store.book[] | select(.\"in stock\" or .price == 8.99) | .price", "8.95\n8.99"},
+		{".store.book[].price | select(. < 9 and . > 8.96)", "8.99"},
+		{". | .count", "2"},
+		{".missing", ""},
+	}
+	for _, tt := range tests {
+		got, err := Filter([]byte(testDocument), tt.expr)
+		if err != nil {
+			t.Errorf("Filter(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if strings.HasPrefix(tt.expr, "$") {
+			got = compact(got)
+		}
+		if got != tt.want {
+			t.Errorf("Filter(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	for _, expr := range []string{"", "$.", "$[", "$.a[?(@.b <)]", "$['a'", ".a |", "items", ".a.", "$.a]", "select(.a"} {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("Compile(%q) succeeded", expr)
+		}
+	}
+}
+
+// compact removes the white space between the JSON tokens of s
+func compact(s string) string {
+	var b bytes.Buffer
+	if err := json.Compact(&b, []byte(s)); err != nil {
+		return s
+	}
+	return b.String()
+}
@@ -0,0 +1,206 @@
+// Package jsonview navigates JSON documents: a tree of their values in the
+// order of the document, with the JSONPath of every value.
+package jsonview
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kind is the kind of a JSON value
+type Kind int
+
+// Kinds of JSON values
+const (
+	Null Kind = iota
+	Bool
+	Number
+	String
+	Array
+	Object
+)
+
+// Node is a value of a JSON document
+type Node struct {
+	// Key is the member name in an object, empty for the root and array items
+	Key string
+	// Index is the position in an array, -1 outside of arrays
+	Index int
+	// Path is the JSONPath of the value, e.g. $.items[0]['content-type']
+	Path     string
+	Kind     Kind
+	Children []*Node
+	// Raw is the value as written in the document
+	Raw json.RawMessage
+}
+
+// Parse parses data into the tree of its values, keeping the order of the
+// object members
+func Parse(data []byte) (*Node, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	p := &parser{data: data, d: d}
+	root, err := p.value("", -1, "$")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid JSON: data after the top-level value")
+	}
+	return root, nil
+}
+
+type parser struct {
+	data []byte
+	d    *json.Decoder
+}
+
+// value parses the next value of the document
+func (p *parser) value(key string, index int, path string) (*Node, error) {
+	start := p.d.InputOffset()
+	token, err := p.d.Token()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	n := &Node{Key: key, Index: index, Path: path}
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '[' {
+			n.Kind = Array
+			for i := 0; p.d.More(); i++ {
+				child, err := p.value("", i, path+"["+strconv.Itoa(i)+"]")
+				if err != nil {
+					return nil, err
+				}
+				n.Children = append(n.Children, child)
+			}
+		} else {
+			n.Kind = Object
+			for p.d.More() {
+				token, err := p.d.Token()
+				if err != nil {
+					return nil, fmt.Errorf("invalid JSON: %w", err)
+				}
+				name := token.(string)
+				child, err := p.value(name, -1, path+MemberPath(name))
+				if err != nil {
+					return nil, err
+				}
+				n.Children = append(n.Children, child)
+			}
+		}
+		// the closing delimiter
+		if _, err := p.d.Token(); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+	case bool:
+		n.Kind = Bool
+	case json.Number:
+		n.Kind = Number
+	case string:
+		n.Kind = String
+	case nil:
+		n.Kind = Null
+	}
+	// the offsets around a value include the separator before it
+	n.Raw = bytes.TrimLeft(p.data[start:p.d.InputOffset()], " \t\r\n:,")
+	return n, nil
+}
+
+// identifier matches the member names written with dot notation
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// MemberPath returns the JSONPath step to the member name of an object
+func MemberPath(name string) string {
+	if identifier.MatchString(name) {
+		return "." + name
+	}
+	return "['" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(name) + "']"
+}
+
+// Label returns the key of the node: the member name, the array index or
+// "$" for the root
+func (n *Node) Label() string {
+	switch {
+	case n.Index >= 0:
+		return "[" + strconv.Itoa(n.Index) + "]"
+	case n.Path == "$":
+		return "$"
+	}
+	return n.Key
+}
+
+// Summary returns the value of a scalar node as written, and the count of
+// items or members of an array or object, e.g. "[3]" or "{2}"
+func (n *Node) Summary() string {
+	switch n.Kind {
+	case Array:
+		return fmt.Sprintf("[%d]", len(n.Children))
+	case Object:
+		return fmt.Sprintf("{%d}", len(n.Children))
+	}
+	return string(n.Raw)
+}
+
+// Value returns the value of the node as JSON, indented for arrays and
+// objects and as written otherwise
+func (n *Node) Value() string {
+	if n.Kind != Array && n.Kind != Object {
+		return string(n.Raw)
+	}
+	var b bytes.Buffer
+	if err := json.Indent(&b, n.Raw, "", "    "); err != nil {
+		return string(n.Raw)
+	}
+	return b.String()
+}
+
+// Text returns the text of a scalar value: a string unquoted, other values
+// as written. Arrays and objects have no text.
+func (n *Node) Text() string {
+	switch n.Kind {
+	case String:
+		var s string
+		json.Unmarshal(n.Raw, &s)
+		return s
+	case Array, Object:
+		return ""
+	}
+	return string(n.Raw)
+}
+
+// Walk calls f for n and its descendants in document order until f
+// returns false
+func (n *Node) Walk(f func(*Node) bool) bool {
+	if !f(n) {
+		return false
+	}
+	for _, child := range n.Children {
+		if !child.Walk(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// Search returns the nodes whose key or scalar text contains query,
+// ignoring case, in document order
+func (n *Node) Search(query string) []*Node {
+	query = strings.ToLower(query)
+	if query == "" {
+		return nil
+	}
+	var found []*Node
+	n.Walk(func(node *Node) bool {
+		if strings.Contains(strings.ToLower(node.Key), query) || strings.Contains(strings.ToLower(node.Text()), query) {
+			found = append(found, node)
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,93 @@
+package jsonview
+
+import (
+	"testing"
+)
+
+const testDocument = `{
+  "store": {
+    "book": [
+      {"title": "Sayings of the Century", "price": 8.95, "in stock": true},
+      {"title": "Moby Dick", "price": 8.99, "isbn": null}
+    ],
+    "owner's name": "Ann"
+  },
+  "count": 2
+}`
+
+func TestParse(t *testing.T) {
+	root, err := Parse([]byte(testDocument))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	var paths []string
+	root.Walk(func(n *Node) bool {
+		paths = append(paths, n.Path)
+		return true
+	})
+	want := []string{
+		"$", "$.store", "$.store.book",
+		"$.store.book[0]", "$.store.book[0].title", "$.store.book[0].price", "$.store.book[0]['in stock']",
+		"$.store.book[1]", "$.store.book[1].title", "$.store.book[1].price", "$.store.book[1].isbn",
+		`$.store['owner\'s name']`, "$.count",
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("paths = %q, want %q", paths, want)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("path %d = %q, want %q", i, paths[i], want[i])
+		}
+	}
+
+	store := root.Children[0]
+	book := store.Children[0]
+	if book.Kind != Array || book.Summary() != "[2]" || store.Summary() != "{2}" {
+		t.Errorf("unexpected summaries: %q %q", book.Summary(), store.Summary())
+	}
+	first := book.Children[0]
+	if first.Label() != "[0]" || first.Children[0].Label() != "title" || root.Label() != "$" {
+		t.Errorf("unexpected labels: %q %q %q", first.Label(), first.Children[0].Label(), root.Label())
+	}
+	if first.Children[1].Kind != Number || first.Children[1].Summary() != "8.95" || first.Children[2].Kind != Bool {
+		t.Errorf("unexpected scalar nodes: %+v", first.Children)
+	}
+	if isbn := book.Children[1].Children[2]; isbn.Kind != Null || isbn.Value() != "null" {
+		t.Errorf("unexpected null node: %+v", isbn)
+	}
+	if got := first.Children[0].Text(); got != "Sayings of the Century" {
+		t.Errorf("Text() = %q", got)
+	}
+	if got, want := book.Children[1].Value(), "{\n    \"title\": \"Moby Dick\",\n    \"price\": 8.99,\n    \"isbn\": null\n}"; got != want {
+		t.Errorf("Value() = %q, want %q", got, want)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, data := range []string{``, `{"a":}`, `[1, 2`, `{} {}`} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Parse(%q) succeeded", data)
+		}
+	}
+}
+
+func TestNode_Search(t *testing.T) {
+	root, err := Parse([]byte(testDocument))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	var paths []string
+	for _, n := range root.Search("MOBY") {
+		paths = append(paths, n.Path)
+	}
+	if len(paths) != 1 || paths[0] != "$.store.book[1].title" {
+		t.Errorf("Search(MOBY) = %q", paths)
+	}
+	if got := len(root.Search("price")); got != 2 {
+		t.Errorf("Search(price) found %d nodes, want 2", got)
+	}
+	if got := root.Search(""); got != nil {
+		t.Errorf("Search(\"\") = %v, want nil", got)
+	}
+}
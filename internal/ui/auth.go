@@ -0,0 +1,310 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/romanitalian/GHOSTman/v2/internal/collection"
+	"github.com/romanitalian/GHOSTman/v2/internal/httpclient"
+	"github.com/romanitalian/GHOSTman/v2/models"
+)
+
+// authInherit is the editor type of a request without its own auth
+const authInherit = ""
+
+// authTypes are the auth types the form edits, in the order they are offered
+var authTypes = []string{authInherit, "noauth", "basic", "bearer", "apikey", "oauth2", "awsv4", "digest"}
+
+var authTypeLabels = map[string]string{
+	authInherit: models.LabelAuthInherit,
+	"noauth":    models.LabelAuthNone,
+	"basic":     models.LabelAuthBasic,
+	"bearer":    models.LabelAuthBearer,
+	"apikey":    models.LabelAuthAPIKey,
+	"oauth2":    models.LabelAuthOAuth2,
+	"awsv4":     models.LabelAuthAWSV4,
+	"digest":    models.LabelAuthDigest,
+}
+
+// authTypeLabel returns the label of an auth type, the type itself when the
+// form does not support it
+func authTypeLabel(authType string) string {
+	if label, ok := authTypeLabels[authType]; ok {
+		return label
+	}
+	return authType
+}
+
+// authEditor edits the auth of a request. Settings of auth types other than
+// the selected one are kept as Postman does.
+type authEditor struct {
+	original  *collection.Auth
+	inherited *collection.Auth
+
+	types      []string
+	typeSelect *widget.Select
+	inheritMsg *widget.Label
+	fields     map[string]fyne.CanvasObject
+	entries    map[string]map[string]*widget.Entry
+	selects    map[string]map[string]*widget.Select
+	defaults   map[string]map[string]string
+
+	content fyne.CanvasObject
+}
+
+// newAuthEditor returns an editor of auth, the request's own auth or nil.
+// inherited is the auth the request inherits from its folders or collection.
+func newAuthEditor(auth, inherited *collection.Auth) *authEditor {
+	e := &authEditor{
+		original:   auth,
+		inherited:  inherited,
+		types:      authTypes,
+		inheritMsg: widget.NewLabel(""),
+		fields:     make(map[string]fyne.CanvasObject),
+		entries:    make(map[string]map[string]*widget.Entry),
+		selects:    make(map[string]map[string]*widget.Select),
+		defaults:   make(map[string]map[string]string),
+	}
+
+	current := authInherit
+	if auth != nil {
+		current = auth.Type
+		if _, ok := authTypeLabels[current]; !ok {
+			e.types = append(append([]string(nil), authTypes...), current)
+		}
+	}
+
+	e.fields["basic"] = e.newFields("basic",
+		authField{key: "username", label: models.LabelUsername},
+		authField{key: "password", label: models.LabelPassword, secret: true},
+	)
+	e.fields["bearer"] = e.newFields("bearer",
+		authField{key: "token", label: models.LabelToken, secret: true},
+	)
+	e.fields["apikey"] = e.newFields("apikey",
+		authField{key: "key", label: models.LabelKey},
+		authField{key: "value", label: models.LabelValue, secret: true},
+		authField{key: "in", label: models.LabelAddTo, options: []string{"header", "query"}},
+	)
+	e.fields["oauth2"] = e.newFields("oauth2",
+		authField{key: "grant_type", label: models.LabelGrantType, options: []string{"client_credentials", "password_credentials", "refresh_token"}},
+		authField{key: "accessTokenUrl", label: models.LabelTokenURL},
+		authField{key: "clientId", label: models.LabelClientID},
+		authField{key: "clientSecret", label: models.LabelClientSecret, secret: true},
+		authField{key: "client_authentication", label: models.LabelClientAuthentication, options: []string{"header", "body"}},
+		authField{key: "username", label: models.LabelUsername},
+		authField{key: "password", label: models.LabelPassword, secret: true},
+		authField{key: "scope", label: models.LabelScope},
+		authField{key: "refreshToken", label: models.LabelRefreshToken, secret: true},
+		authField{key: "headerPrefix", label: models.LabelHeaderPrefix},
+		authField{key: "addTokenTo", label: models.LabelAddTo, options: []string{"header", "queryParams"}},
+	)
+	e.fields["digest"] = e.newFields("digest",
+		authField{key: "username", label: models.LabelUsername},
+		authField{key: "password", label: models.LabelPassword, secret: true},
+	)
+	e.fields["awsv4"] = e.newFields("awsv4",
+		authField{key: "accessKey", label: models.LabelAccessKey},
+		authField{key: "secretKey", label: models.LabelSecretKey, secret: true},
+		authField{key: "sessionToken", label: models.LabelSessionToken, secret: true},
+		authField{key: "region", label: models.LabelRegion},
+		authField{key: "service", label: models.LabelService},
+	)
+
+	stack := container.NewStack()
+	for _, fields := range e.fields {
+		fields.Hide()
+		stack.Add(fields)
+	}
+
+	labels := make([]string, len(e.types))
+	for i, t := range e.types {
+		labels[i] = authTypeLabel(t)
+	}
+	e.typeSelect = widget.NewSelect(labels, func(string) { e.showFields() })
+	e.typeSelect.SetSelected(authTypeLabel(current))
+
+	e.content = container.NewVBox(e.typeSelect, e.inheritMsg, stack)
+	return e
+}
+
+// authField describes a setting of an auth type. A field with options is
+// edited with a select defaulting to the first option, otherwise with an entry.
+type authField struct {
+	key     string
+	label   string
+	secret  bool
+	options []string
+}
+
+// newFields returns a form with a widget per field of authType, filled with
+// the stored settings
+func (e *authEditor) newFields(authType string, fields ...authField) *widget.Form {
+	frm := &widget.Form{}
+	e.entries[authType] = make(map[string]*widget.Entry)
+	e.selects[authType] = make(map[string]*widget.Select)
+	e.defaults[authType] = make(map[string]string)
+	for _, f := range fields {
+		if f.options != nil {
+			value := e.param(authType, f.key)
+			options := f.options
+			if value != "" && !slices.Contains(options, value) {
+				// keep values the form does not support
+				options = append(slices.Clip(options), value)
+			}
+			sel := widget.NewSelect(options, nil)
+			sel.SetSelected(f.options[0])
+			if value != "" {
+				sel.SetSelected(value)
+			}
+			e.selects[authType][f.key] = sel
+			e.defaults[authType][f.key] = f.options[0]
+			frm.Append(f.label, sel)
+			continue
+		}
+
+		entry := widget.NewEntry()
+		if f.secret {
+			entry = widget.NewPasswordEntry()
+		}
+		entry.SetText(e.param(authType, f.key))
+		e.entries[authType][f.key] = entry
+		frm.Append(f.label, entry)
+	}
+	return frm
+}
+
+// param returns the stored setting key of authType
+func (e *authEditor) param(authType, key string) string {
+	if e.original == nil {
+		return ""
+	}
+	for _, attr := range e.original.Params[authType] {
+		if attr.Key == key {
+			return attr.String()
+		}
+	}
+	return ""
+}
+
+// selectedType returns the auth type chosen in the editor
+func (e *authEditor) selectedType() string {
+	return e.types[e.typeSelect.SelectedIndex()]
+}
+
+// showFields shows the fields of the selected type
+func (e *authEditor) showFields() {
+	selected := e.selectedType()
+	for t, fields := range e.fields {
+		if t == selected {
+			fields.Show()
+		} else {
+			fields.Hide()
+		}
+	}
+
+	if selected != authInherit {
+		e.inheritMsg.Hide()
+		return
+	}
+	if e.inherited == nil {
+		e.inheritMsg.SetText(models.MsgNoInheritedAuth)
+	} else {
+		e.inheritMsg.SetText(fmt.Sprintf(models.MsgInheritedAuth, authTypeLabel(e.inherited.Type)))
+	}
+	e.inheritMsg.Show()
+}
+
+// auth returns the request's own auth as edited, nil when it inherits
+func (e *authEditor) auth() *collection.Auth {
+	selected := e.selectedType()
+	if selected == authInherit {
+		return nil
+	}
+
+	auth := &collection.Auth{}
+	if e.original != nil {
+		auth = e.original.Clone()
+	}
+	auth.Type = selected
+	for key, entry := range e.entries[selected] {
+		if entry.Text != "" || auth.Get(key) != "" {
+			auth.Set(key, entry.Text)
+		}
+	}
+	for key, sel := range e.selects[selected] {
+		if sel.Selected != e.defaults[selected][key] || auth.Get(key) != "" {
+			auth.Set(key, sel.Selected)
+		}
+	}
+	return auth
+}
+
+// effectiveAuth returns the auth that applies when the request is sent
+func (e *authEditor) effectiveAuth() *collection.Auth {
+	return collection.EffectiveAuth(e.auth(), e.inherited)
+}
+
+// authenticator returns the authenticator of auth with its settings resolved
+// by resolve with the variables of environment, nil when the request is sent
+// without auth
+func authenticator(auth *collection.Auth, environment string, resolve func(string) (string, error)) (httpclient.Authenticator, error) {
+	if auth == nil {
+		return nil, nil
+	}
+	var resolveErr error
+	get := func(key string) string {
+		value, err := resolve(auth.Get(key))
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+		return value
+	}
+
+	var a httpclient.Authenticator
+	switch auth.Type {
+	case "noauth":
+		return nil, nil
+	case "basic":
+		a = httpclient.BasicAuth{Username: get("username"), Password: get("password")}
+	case "bearer":
+		a = httpclient.BearerAuth{Token: get("token")}
+	case "apikey":
+		a = httpclient.APIKeyAuth{Key: get("key"), Value: get("value"), InQuery: auth.Get("in") == "query"}
+	case "oauth2":
+		a = &httpclient.OAuth2Auth{
+			TokenURL:     get("accessTokenUrl"),
+			GrantType:    auth.Get("grant_type"),
+			ClientID:     get("clientId"),
+			ClientSecret: get("clientSecret"),
+			ClientInBody: auth.Get("client_authentication") == "body",
+			Username:     get("username"),
+			Password:     get("password"),
+			Scope:        get("scope"),
+			RefreshToken: get("refreshToken"),
+			HeaderPrefix: get("headerPrefix"),
+			InQuery:      auth.Get("addTokenTo") == "queryParams",
+			Environment:  environment,
+		}
+	case "digest":
+		a = httpclient.DigestAuth{Username: get("username"), Password: get("password")}
+	case "awsv4":
+		a = httpclient.AWSV4Auth{
+			AccessKey:    get("accessKey"),
+			SecretKey:    get("secretKey"),
+			SessionToken: get("sessionToken"),
+			Region:       get("region"),
+			Service:      get("service"),
+		}
+	default:
+		return nil, fmt.Errorf("unsupported auth type %q", auth.Type)
+	}
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+	return a, nil
+}
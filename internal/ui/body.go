@@ -0,0 +1,371 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/romanitalian/GHOSTman/v2/internal/collection"
+	"github.com/romanitalian/GHOSTman/v2/internal/httpclient"
+	"github.com/romanitalian/GHOSTman/v2/models"
+)
+
+// bodyModeNone is the editor mode of a request without body
+const bodyModeNone = ""
+
+// bodyModes are the Postman body modes, in the order they are offered
+var bodyModes = []string{bodyModeNone, "raw", "urlencoded", "formdata", "file", "graphql"}
+
+var bodyModeLabels = map[string]string{
+	bodyModeNone: models.LabelBodyNone,
+	"raw":        models.LabelBodyRaw,
+	"urlencoded": models.LabelBodyURLEncoded,
+	"formdata":   models.LabelBodyFormData,
+	"file":       models.LabelBodyFile,
+	"graphql":    models.LabelBodyGraphQL,
+}
+
+// rawLanguages are the languages of a raw body, the first is the default
+var rawLanguages = []string{"text", "json", "javascript", "html", "xml"}
+
+// bodyEditor edits the request body with an editor per body mode
+type bodyEditor struct {
+	original *collection.Body
+
+	modes            []string
+	modeSelect       *widget.Select
+	raw              *widget.Entry
+	rawLanguage      *widget.Select
+	urlencoded       *keyValueTable
+	formdata         *keyValueTable
+	file             *widget.Entry
+	graphQLQuery     *widget.Entry
+	graphQLVariables *widget.Entry
+	editors          map[string]fyne.CanvasObject
+
+	// OnChanged is called after every edit of the body
+	OnChanged func()
+
+	content fyne.CanvasObject
+}
+
+// newBodyEditor returns an editor of body, which may be nil
+func newBodyEditor(body *collection.Body) *bodyEditor {
+	e := &bodyEditor{
+		original:         body,
+		raw:              widget.NewMultiLineEntry(),
+		rawLanguage:      widget.NewSelect(rawLanguages, nil),
+		urlencoded:       newKeyValueTable(false),
+		formdata:         newKeyValueTable(true),
+		file:             widget.NewEntry(),
+		graphQLQuery:     widget.NewMultiLineEntry(),
+		graphQLVariables: widget.NewMultiLineEntry(),
+	}
+
+	mode := "raw"
+	if body == nil {
+		body = &collection.Body{}
+	} else if body.Mode != "" || body.Raw == "" {
+		mode = body.Mode
+	}
+
+	// Size the raw editor to its text
+	e.raw.SetText(body.Raw)
+	e.raw.SetMinRowsVisible(strings.Count(body.Raw, "\n") + 1)
+	e.rawLanguage.SetSelected(rawLanguages[0])
+	if lang := body.RawLanguage(); lang != "" {
+		e.rawLanguage.SetSelected(lang)
+	}
+
+	var urlencoded []keyValueRow
+	for _, p := range body.URLEncoded {
+		urlencoded = append(urlencoded, keyValueRow{Key: p.Key, Value: p.Value, Disabled: p.Disabled})
+	}
+	e.urlencoded.SetRows(urlencoded)
+
+	var formdata []keyValueRow
+	for _, p := range body.FormData {
+		row := keyValueRow{Key: p.Key, Value: p.Value, Disabled: p.Disabled, File: p.Type == "file"}
+		if row.File {
+			row.Value = strings.Join(p.Files(), ",")
+		}
+		formdata = append(formdata, row)
+	}
+	e.formdata.SetRows(formdata)
+
+	if body.File != nil {
+		e.file.SetText(body.File.Src)
+	}
+	browse := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			defer reader.Close()
+			e.file.SetText(reader.URI().Path())
+		}, windowFor(e.content))
+	})
+
+	if body.GraphQL != nil {
+		e.graphQLQuery.SetText(body.GraphQL.Query)
+		e.graphQLVariables.SetText(body.GraphQL.Variables)
+	}
+	e.graphQLQuery.SetMinRowsVisible(6)
+	e.graphQLVariables.SetMinRowsVisible(3)
+
+	e.editors = map[string]fyne.CanvasObject{
+		bodyModeNone: widget.NewLabel(models.MsgNoBody),
+		"raw":        container.NewBorder(container.NewHBox(e.rawLanguage), nil, nil, nil, e.raw),
+		"urlencoded": e.urlencoded.content,
+		"formdata":   e.formdata.content,
+		"file":       container.NewBorder(nil, nil, nil, browse, e.file),
+		"graphql": widget.NewForm(
+			widget.NewFormItem(models.LabelQuery, e.graphQLQuery),
+			widget.NewFormItem(models.LabelVariables, e.graphQLVariables),
+		),
+	}
+	stack := container.NewStack()
+	for _, editor := range e.editors {
+		editor.Hide()
+		stack.Add(editor)
+	}
+
+	e.modes = bodyModes
+	if _, ok := bodyModeLabels[mode]; !ok {
+		// keep a mode the form does not support, sent without body
+		e.modes = append(append([]string(nil), bodyModes...), mode)
+		e.editors[mode] = widget.NewLabel(models.MsgNoBody)
+		stack.Add(e.editors[mode])
+	}
+	labels := make([]string, len(e.modes))
+	for i, m := range e.modes {
+		labels[i] = bodyModeLabel(m)
+	}
+	e.modeSelect = widget.NewSelect(labels, nil)
+	e.modeSelect.SetSelected(bodyModeLabel(mode))
+	e.showEditor()
+	e.modeSelect.OnChanged = func(string) {
+		e.showEditor()
+		e.changed()
+	}
+
+	onChanged := func(string) { e.changed() }
+	e.raw.OnChanged = onChanged
+	e.rawLanguage.OnChanged = onChanged
+	e.file.OnChanged = onChanged
+	e.graphQLQuery.OnChanged = onChanged
+	e.graphQLVariables.OnChanged = onChanged
+	e.urlencoded.OnChanged = e.changed
+	e.formdata.OnChanged = e.changed
+
+	e.content = container.NewVBox(e.modeSelect, stack)
+	return e
+}
+
+// bodyModeLabel returns the label of a body mode, the mode itself when the
+// form does not support it
+func bodyModeLabel(mode string) string {
+	if label, ok := bodyModeLabels[mode]; ok {
+		return label
+	}
+	return mode
+}
+
+func (e *bodyEditor) changed() {
+	if e.OnChanged != nil {
+		e.OnChanged()
+	}
+}
+
+// mode returns the body mode chosen in the editor
+func (e *bodyEditor) mode() string {
+	return e.modes[e.modeSelect.SelectedIndex()]
+}
+
+// showEditor shows the editor of the selected mode
+func (e *bodyEditor) showEditor() {
+	selected := e.mode()
+	for m, editor := range e.editors {
+		if m == selected {
+			editor.Show()
+		} else {
+			editor.Hide()
+		}
+	}
+}
+
+// text returns the body as text for the preview of the request
+func (e *bodyEditor) text() string {
+	var b strings.Builder
+	switch e.mode() {
+	case "raw":
+		return e.raw.Text
+	case "urlencoded":
+		for _, row := range e.urlencoded.Rows() {
+			if !row.Disabled {
+				b.WriteString(row.Key + "=" + row.Value + "\n")
+			}
+		}
+	case "formdata":
+		for _, row := range e.formdata.Rows() {
+			if row.Disabled {
+				continue
+			}
+			if row.File {
+				b.WriteString(row.Key + ": @" + row.Value + "\n")
+			} else {
+				b.WriteString(row.Key + ": " + row.Value + "\n")
+			}
+		}
+	case "file":
+		return "@" + e.file.Text
+	case "graphql":
+		return e.graphQLQuery.Text + "\n" + e.graphQLVariables.Text
+	}
+	return b.String()
+}
+
+// body returns the request body as edited. Postman attributes of the fields
+// are kept while their key stays the same.
+func (e *bodyEditor) body() *collection.Body {
+	mode := e.mode()
+	if e.original == nil && (mode == bodyModeNone || mode == "raw" && e.raw.Text == "") {
+		return nil
+	}
+
+	var b collection.Body
+	if e.original != nil {
+		b = *e.original
+	}
+	b.Mode = mode
+
+	switch mode {
+	case "raw":
+		b.Raw = e.raw.Text
+		if e.rawLanguage.Selected != rawLanguages[0] || b.RawLanguage() != "" {
+			// keep the options as they are when they cannot be parsed
+			_ = b.SetRawLanguage(e.rawLanguage.Selected)
+		}
+	case "urlencoded":
+		var params []collection.QueryParam
+		for i, row := range e.urlencoded.Rows() {
+			p := collection.QueryParam{Key: row.Key}
+			if i < len(b.URLEncoded) && b.URLEncoded[i].Key == row.Key {
+				p = b.URLEncoded[i]
+			}
+			p.Value = row.Value
+			p.Disabled = row.Disabled
+			params = append(params, p)
+		}
+		b.URLEncoded = params
+	case "formdata":
+		var params []collection.FormParam
+		for i, row := range e.formdata.Rows() {
+			p := collection.FormParam{Key: row.Key}
+			if i < len(b.FormData) && b.FormData[i].Key == row.Key {
+				p = b.FormData[i]
+			}
+			p.Disabled = row.Disabled
+			if row.File {
+				p.Type = "file"
+				p.Value = ""
+				var files []string
+				if row.Value != "" {
+					files = strings.Split(row.Value, ",")
+				}
+				_ = p.SetFiles(files)
+			} else {
+				p.Type = "text"
+				p.Value = row.Value
+				p.Src = nil
+			}
+			params = append(params, p)
+		}
+		b.FormData = params
+	case "file":
+		file := collection.BodyFile{}
+		if b.File != nil {
+			file = *b.File
+		}
+		file.Src = e.file.Text
+		b.File = &file
+	case "graphql":
+		gql := collection.GraphQL{}
+		if b.GraphQL != nil {
+			gql = *b.GraphQL
+		}
+		gql.Query = e.graphQLQuery.Text
+		gql.Variables = e.graphQLVariables.Text
+		b.GraphQL = &gql
+	}
+	return &b
+}
+
+// encoder returns a function encoding the body as edited, with its texts
+// resolved by resolve. Files are read when the function is called.
+func (e *bodyEditor) encoder(resolve func(string) (string, error)) (func() (httpclient.Body, error), error) {
+	b := e.body()
+	if b == nil {
+		return func() (httpclient.Body, error) { return httpclient.Body{}, nil }, nil
+	}
+
+	var resolveErr error
+	get := func(s string) string {
+		value, err := resolve(s)
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+		return value
+	}
+
+	var encode func() (httpclient.Body, error)
+	switch b.Mode {
+	case "raw":
+		body := httpclient.RawBody(get(b.Raw), b.RawLanguage())
+		encode = func() (httpclient.Body, error) { return body, nil }
+	case "urlencoded":
+		var fields []httpclient.FormField
+		for _, p := range b.URLEncoded {
+			if !p.Disabled {
+				fields = append(fields, httpclient.FormField{Key: get(p.Key), Value: get(p.Value)})
+			}
+		}
+		encode = func() (httpclient.Body, error) { return httpclient.URLEncodedBody(fields), nil }
+	case "formdata":
+		var fields []httpclient.FormField
+		for _, p := range b.FormData {
+			if p.Disabled {
+				continue
+			}
+			if p.Type != "file" {
+				fields = append(fields, httpclient.FormField{Key: get(p.Key), Value: get(p.Value), ContentType: p.ContentType})
+				continue
+			}
+			for _, file := range p.Files() {
+				fields = append(fields, httpclient.FormField{Key: get(p.Key), File: get(file), ContentType: p.ContentType})
+			}
+		}
+		encode = func() (httpclient.Body, error) { return httpclient.MultipartBody(fields) }
+	case "file":
+		path := get(b.File.Src)
+		encode = func() (httpclient.Body, error) {
+			if path == "" {
+				return httpclient.Body{}, nil
+			}
+			return httpclient.FileBody(path)
+		}
+	case "graphql":
+		query, variables := get(b.GraphQL.Query), get(b.GraphQL.Variables)
+		encode = func() (httpclient.Body, error) { return httpclient.GraphQLBody(query, variables) }
+	default:
+		encode = func() (httpclient.Body, error) { return httpclient.Body{}, nil }
+	}
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+	return encode, nil
+}
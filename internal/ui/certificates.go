@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/romanitalian/GHOSTman/v2/models"
+)
+
+// updateCertificates shows in form the protocol and cipher suite of state
+// and the certificate chain of the server, leaf first
+func updateCertificates(form *widget.Form, state *tls.ConnectionState) {
+	form.Items = nil
+	defer form.Refresh()
+	if state == nil {
+		form.Append("", widget.NewLabel(models.MsgNoTLS))
+		return
+	}
+	form.Append(models.LabelTLSVersion, newValueLabel(tls.VersionName(state.Version)))
+	form.Append(models.LabelCipherSuite, newValueLabel(tls.CipherSuiteName(state.CipherSuite)))
+	for i, c := range state.PeerCertificates {
+		form.Append(fmt.Sprintf(models.LabelCertificate, i+1), newValueLabel(certificateText(c)))
+	}
+}
+
+// certificateText returns the fields of c shown
+func certificateText(c *x509.Certificate) string {
+	names := append([]string(nil), c.DNSNames...)
+	for _, ip := range c.IPAddresses {
+		names = append(names, ip.String())
+	}
+	for _, u := range c.URIs {
+		names = append(names, u.String())
+	}
+	names = append(names, c.EmailAddresses...)
+	fingerprint := sha256.Sum256(c.Raw)
+	return fmt.Sprintf(models.MsgCertificate,
+		c.Subject, c.Issuer,
+		c.NotBefore.UTC().Format(time.DateTime), c.NotAfter.UTC().Format(time.DateTime),
+		strings.Join(names, ", "),
+		hexBytes(c.SerialNumber.Bytes()), hexBytes(fingerprint[:]))
+}
+
+// hexBytes formats b as colon separated hex bytes, e.g. "0A:FF"
+func hexBytes(b []byte) string {
+	parts := make([]string, len(b))
+	for i, v := range b {
+		parts[i] = fmt.Sprintf("%02X", v)
+	}
+	return strings.Join(parts, ":")
+}
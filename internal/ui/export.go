@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/romanitalian/GHOSTman/v2/internal/httpclient"
+	"github.com/romanitalian/GHOSTman/v2/models"
+)
+
+// newResponseActions returns the buttons saving the body of the response
+// that resp returns, and copying its body, headers or the whole exchange as
+// HAR to the clipboard
+func newResponseActions(resp func() *httpclient.Response) *fyne.Container {
+	var actions *fyne.Container
+	copyText := func(text func(*httpclient.Response) (string, error)) func() {
+		return func() {
+			r := resp()
+			if r == nil {
+				return
+			}
+			s, err := text(r)
+			if err != nil {
+				dialog.ShowError(err, windowFor(actions))
+				return
+			}
+			fyne.CurrentApp().Clipboard().SetContent(s)
+		}
+	}
+	actions = container.NewHBox(
+		widget.NewButtonWithIcon(models.LabelSaveBody, theme.DocumentSaveIcon(), func() {
+			if r := resp(); r != nil {
+				saveResponseBody(r, windowFor(actions))
+			}
+		}),
+		widget.NewButtonWithIcon(models.LabelCopyBody, theme.ContentCopyIcon(), copyText(func(r *httpclient.Response) (string, error) {
+			return r.Text(), nil
+		})),
+		widget.NewButtonWithIcon(models.LabelCopyHeaders, theme.ContentCopyIcon(), copyText(func(r *httpclient.Response) (string, error) {
+			return r.HeaderText(), nil
+		})),
+		widget.NewButtonWithIcon(models.LabelCopyHAR, theme.ContentCopyIcon(), copyText(func(r *httpclient.Response) (string, error) {
+			har, err := r.HAR()
+			return string(har), err
+		})),
+	)
+	return actions
+}
+
+// saveResponseBody saves the raw bytes of the body of resp, the whole body
+// when it is kept in a file, to a file chosen by the user. The name offered
+// is resp.Filename.
+func saveResponseBody(resp *httpclient.Response, w fyne.Window) {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+		var body io.Reader = bytes.NewReader(resp.Body)
+		if resp.BodyFile != "" {
+			f, err := os.Open(resp.BodyFile)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			defer f.Close()
+			body = f
+		}
+		if _, err := io.Copy(writer, body); err != nil {
+			dialog.ShowError(err, w)
+		}
+	}, w)
+	save.SetFileName(resp.Filename())
+	save.Show()
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/romanitalian/GHOSTman/v2/internal/httpclient"
+	"github.com/romanitalian/GHOSTman/v2/internal/jsonview"
+	"github.com/romanitalian/GHOSTman/v2/models"
+)
+
+// preferenceResponseFilter prefixes the preference holding the last filter
+// of a request, followed by the form ID
+const preferenceResponseFilter = "responseFilter."
+
+// responseFilter filters a JSON response body with a JSONPath or jq
+// expression as it is typed. The last filter of each request is kept in the
+// preferences and applied to the following responses.
+type responseFilter struct {
+	entry  *widget.Entry
+	status *widget.Label
+	result *widget.Entry
+	root   *jsonview.Node
+	// key is the preference of the filter, empty when it is not kept
+	key string
+
+	content fyne.CanvasObject
+}
+
+func newResponseFilter(formID string) *responseFilter {
+	f := &responseFilter{
+		entry:  widget.NewEntry(),
+		status: widget.NewLabel(""),
+		result: newResponseEntry(),
+	}
+	if formID != "" {
+		f.key = preferenceResponseFilter + formID
+		f.entry.SetText(fyne.CurrentApp().Preferences().String(f.key))
+	}
+	f.entry.SetPlaceHolder(models.PlaceholderFilterJSON)
+	f.entry.OnChanged = func(expr string) {
+		if f.key != "" {
+			fyne.CurrentApp().Preferences().SetString(f.key, expr)
+		}
+		f.apply()
+	}
+	f.result.SetMinRowsVisible(10)
+	f.result.Hide()
+	f.status.Wrapping = fyne.TextWrapWord
+	f.content = container.NewVBox(f.entry, f.status, f.result)
+	f.content.Hide()
+	return f
+}
+
+// update filters the body of resp, the filter is hidden unless it is JSON
+func (f *responseFilter) update(resp *httpclient.Response) {
+	f.root = nil
+	if resp != nil && resp.Kind() == httpclient.KindJSON {
+		f.root, _ = jsonview.Parse(resp.Body)
+	}
+	if f.root == nil {
+		f.content.Hide()
+		return
+	}
+	f.apply()
+	f.content.Show()
+}
+
+// apply shows the result of the filter, or why it is invalid
+func (f *responseFilter) apply() {
+	expr := strings.TrimSpace(f.entry.Text)
+	if f.root == nil || expr == "" {
+		f.status.SetText("")
+		f.result.Hide()
+		return
+	}
+	q, err := jsonview.Compile(expr)
+	if err != nil {
+		f.status.SetText(err.Error())
+		return
+	}
+	nodes := q.Eval(f.root)
+	f.status.SetText(fmt.Sprintf(models.MsgFilterResults, len(nodes)))
+	f.result.SetText(q.Format(nodes))
+	f.result.Show()
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/romanitalian/GHOSTman/v2/internal/collection"
+	"github.com/romanitalian/GHOSTman/v2/models"
+)
+
+// headerNames are the standard request headers suggested in the header table
+var headerNames = []string{
+	"Accept", "Accept-Charset", "Accept-Encoding", "Accept-Language",
+	"Authorization", "Cache-Control", "Connection", "Content-Disposition",
+	"Content-Encoding", "Content-Language", "Content-Length", "Content-MD5",
+	"Content-Type", "Cookie", "Date", "DNT", "Expect", "Forwarded", "From",
+	"Host", "If-Match", "If-Modified-Since", "If-None-Match", "If-Range",
+	"If-Unmodified-Since", "Max-Forwards", "Origin", "Pragma",
+	"Proxy-Authorization", "Range", "Referer", "TE", "Upgrade", "User-Agent",
+	"Via", "Warning", "X-API-Key", "X-Correlation-ID", "X-Forwarded-For",
+	"X-Forwarded-Host", "X-Forwarded-Proto", "X-Request-ID", "X-Requested-With",
+}
+
+// headerEditor edits the request headers in a table. A header may be
+// repeated to send several values.
+type headerEditor struct {
+	// original are the headers last loaded or saved
+	original collection.HeaderList
+	table    *keyValueTable
+}
+
+// newHeaderEditor returns an editor of headers
+func newHeaderEditor(headers collection.HeaderList) *headerEditor {
+	e := &headerEditor{original: headers, table: newKeyValueTable(false)}
+	e.table.CompleteKeys(headerNames)
+	rows := make([]keyValueRow, len(headers))
+	for i, h := range headers {
+		rows[i] = keyValueRow{Key: h.Key, Value: h.Value, Disabled: h.Disabled}
+	}
+	e.table.SetRows(rows)
+	return e
+}
+
+// text returns the enabled headers with a "Key: Value" line each
+func (e *headerEditor) text() string {
+	var b strings.Builder
+	for _, row := range e.table.Rows() {
+		if !row.Disabled && strings.TrimSpace(row.Key) != "" {
+			b.WriteString(strings.TrimSpace(row.Key) + ": " + row.Value + "\n")
+		}
+	}
+	return b.String()
+}
+
+// resolvedText is text with the variables of the keys and values resolved.
+// Resolved values with line breaks are rejected, they would add headers.
+func (e *headerEditor) resolvedText(resolve func(string) (string, error)) (string, error) {
+	var b strings.Builder
+	for _, row := range e.table.Rows() {
+		if row.Disabled || strings.TrimSpace(row.Key) == "" {
+			continue
+		}
+		key, err := resolve(strings.TrimSpace(row.Key))
+		if err != nil {
+			return "", err
+		}
+		value, err := resolve(row.Value)
+		if err != nil {
+			return "", err
+		}
+		if strings.ContainsAny(key+value, "\r\n") {
+			return "", fmt.Errorf(models.ErrHeaderLineBreak, key)
+		}
+		b.WriteString(key + ": " + value + "\n")
+	}
+	return b.String(), nil
+}
+
+// headers returns the headers as edited. Postman attributes of the headers,
+// like their description, are kept while their key stays the same.
+func (e *headerEditor) headers() collection.HeaderList {
+	var edited collection.HeaderList
+	used := make([]bool, len(e.original))
+	for _, row := range e.table.Rows() {
+		key := strings.TrimSpace(row.Key)
+		if key == "" {
+			continue
+		}
+		h := collection.Header{Key: key}
+		// the first header of the key not matched yet, in order
+		for i, o := range e.original {
+			if !used[i] && o.Key == key {
+				h, used[i] = o, true
+				break
+			}
+		}
+		h.Value = row.Value
+		h.Disabled = row.Disabled
+		edited = append(edited, h)
+	}
+	return edited
+}
+
+// saved makes headers the headers matched by later edits
+func (e *headerEditor) saved(headers collection.HeaderList) {
+	e.original = headers
+}
@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/romanitalian/GHOSTman/v2/internal/jsonview"
+	"github.com/romanitalian/GHOSTman/v2/models"
+)
+
+// jsonTreeView shows a JSON document as a tree of collapsible nodes, with a
+// search of keys and values and copy actions for the selected node
+type jsonTreeView struct {
+	root *jsonview.Node
+	// nodes and parents are keyed by the JSONPath of the nodes, their tree ID
+	nodes   map[string]*jsonview.Node
+	parents map[string]string
+
+	tree       *widget.Tree
+	search     *widget.Entry
+	matches    []*jsonview.Node
+	match      int
+	matchLabel *widget.Label
+	selected   *jsonview.Node
+	pathLabel  *widget.Label
+	copyPath   *widget.Button
+	copyValue  *widget.Button
+
+	content fyne.CanvasObject
+}
+
+func newJSONTreeView() *jsonTreeView {
+	v := &jsonTreeView{
+		search:     widget.NewEntry(),
+		matchLabel: widget.NewLabel(""),
+		pathLabel:  widget.NewLabel(""),
+	}
+	v.tree = widget.NewTree(v.childIDs, v.isBranch, func(bool) fyne.CanvasObject {
+		label := widget.NewLabel("")
+		label.Truncation = fyne.TextTruncateEllipsis
+		return label
+	}, v.updateItem)
+	v.tree.OnSelected = func(id widget.TreeNodeID) {
+		v.selected = v.nodes[id]
+		v.pathLabel.SetText(id)
+		v.copyPath.Enable()
+		v.copyValue.Enable()
+	}
+
+	v.copyPath = widget.NewButtonWithIcon(models.LabelCopyPath, theme.ContentCopyIcon(), func() {
+		if v.selected != nil {
+			fyne.CurrentApp().Clipboard().SetContent(v.selected.Path)
+		}
+	})
+	v.copyValue = widget.NewButtonWithIcon(models.LabelCopyValue, theme.ContentCopyIcon(), func() {
+		if v.selected != nil {
+			fyne.CurrentApp().Clipboard().SetContent(v.selected.Value())
+		}
+	})
+	v.copyPath.Disable()
+	v.copyValue.Disable()
+	v.pathLabel.Truncation = fyne.TextTruncateEllipsis
+
+	v.search.SetPlaceHolder(models.PlaceholderSearchJSON)
+	v.search.OnChanged = func(query string) {
+		v.matches, v.match = nil, 0
+		if v.root != nil {
+			v.matches = v.root.Search(query)
+		}
+		v.showMatch()
+	}
+	v.search.OnSubmitted = func(string) { v.nextMatch(1) }
+	prev := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() { v.nextMatch(-1) })
+	next := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() { v.nextMatch(1) })
+
+	searchBar := container.NewBorder(nil, nil, nil, container.NewHBox(v.matchLabel, prev, next), v.search)
+	actions := container.NewBorder(nil, nil, nil, container.NewHBox(v.copyPath, v.copyValue), v.pathLabel)
+	// the tree scrolls by itself, give it about the height of the response entry
+	height := canvas.NewRectangle(color.Transparent)
+	height.SetMinSize(fyne.NewSize(0, 500))
+	v.content = container.NewBorder(container.NewVBox(searchBar, actions), nil, nil, nil, container.NewStack(height, v.tree))
+	return v
+}
+
+// set shows root, with its top-level nodes open
+func (v *jsonTreeView) set(root *jsonview.Node) {
+	v.root, v.selected = root, nil
+	v.nodes = make(map[string]*jsonview.Node)
+	v.parents = make(map[string]string)
+	root.Walk(func(n *jsonview.Node) bool {
+		v.nodes[n.Path] = n
+		for _, child := range n.Children {
+			v.parents[child.Path] = n.Path
+		}
+		return true
+	})
+
+	v.pathLabel.SetText("")
+	v.copyPath.Disable()
+	v.copyValue.Disable()
+	v.tree.UnselectAll()
+	v.tree.CloseAllBranches()
+	v.tree.Refresh()
+	v.tree.OpenBranch(root.Path)
+	v.search.SetText("")
+}
+
+func (v *jsonTreeView) childIDs(id widget.TreeNodeID) []widget.TreeNodeID {
+	if id == "" {
+		if v.root == nil {
+			return nil
+		}
+		return []widget.TreeNodeID{v.root.Path}
+	}
+	node := v.nodes[id]
+	if node == nil {
+		return nil
+	}
+	ids := make([]widget.TreeNodeID, len(node.Children))
+	for i, child := range node.Children {
+		ids[i] = child.Path
+	}
+	return ids
+}
+
+func (v *jsonTreeView) isBranch(id widget.TreeNodeID) bool {
+	if id == "" {
+		return true
+	}
+	node := v.nodes[id]
+	return node != nil && (node.Kind == jsonview.Array || node.Kind == jsonview.Object)
+}
+
+func (v *jsonTreeView) updateItem(id widget.TreeNodeID, _ bool, obj fyne.CanvasObject) {
+	if node := v.nodes[id]; node != nil {
+		obj.(*widget.Label).SetText(node.Label() + ": " + node.Summary())
+	}
+}
+
+// nextMatch selects the match after the current one, or before it for a
+// negative step
+func (v *jsonTreeView) nextMatch(step int) {
+	if len(v.matches) == 0 {
+		return
+	}
+	v.match = (v.match + step + len(v.matches)) % len(v.matches)
+	v.showMatch()
+}
+
+// showMatch opens the branches up to the current match and selects it
+func (v *jsonTreeView) showMatch() {
+	if len(v.matches) == 0 {
+		v.matchLabel.SetText("")
+		if v.search.Text != "" {
+			v.matchLabel.SetText(models.MsgNoMatches)
+		}
+		return
+	}
+	v.matchLabel.SetText(fmt.Sprintf("%d/%d", v.match+1, len(v.matches)))
+	id := v.matches[v.match].Path
+	for parent, ok := v.parents[id]; ok; parent, ok = v.parents[parent] {
+		v.tree.OpenBranch(parent)
+	}
+	v.tree.Select(id)
+	v.tree.ScrollTo(id)
+}
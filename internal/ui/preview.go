@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/romanitalian/GHOSTman/v2/internal/collection"
+	"github.com/romanitalian/GHOSTman/v2/models"
+)
+
+// requestPreview shows the request with its variables resolved.
+// Placeholders that are undefined or part of a reference cycle are
+// highlighted as warnings; dynamic variables are highlighted as they get
+// their values only when sent.
+type requestPreview struct {
+	url     *widget.RichText
+	details *widget.RichText
+	warning *widget.Label
+	content fyne.CanvasObject
+}
+
+func newRequestPreview() *requestPreview {
+	p := &requestPreview{
+		url:     widget.NewRichText(),
+		details: widget.NewRichText(),
+		warning: widget.NewLabel(""),
+	}
+	p.url.Wrapping = fyne.TextWrapBreak
+	p.details.Wrapping = fyne.TextWrapBreak
+	p.warning.Importance = widget.WarningImportance
+	p.warning.Wrapping = fyne.TextWrapWord
+	p.warning.Hide()
+
+	p.content = container.NewVBox(
+		p.url,
+		p.warning,
+		widget.NewAccordion(widget.NewAccordionItem(models.LabelResolvedRequest, p.details)),
+	)
+	return p
+}
+
+// update resolves the editor texts with vars and shows the result
+func (p *requestPreview) update(vars map[string]string, rawURL, headers, body string) {
+	var unresolved []string
+	var resolveErr error
+	resolve := func(s string) []widget.RichTextSegment {
+		resolved, undefined, err := collection.ResolveVariables(s, vars)
+		for _, name := range undefined {
+			if !slices.Contains(unresolved, name) {
+				unresolved = append(unresolved, name)
+			}
+		}
+		if resolveErr == nil {
+			resolveErr = err
+		}
+		return previewSegments(resolved)
+	}
+
+	p.url.Segments = resolve(rawURL)
+	p.url.Refresh()
+
+	p.details.Segments = append(
+		resolve(headers),
+		append([]widget.RichTextSegment{&widget.SeparatorSegment{}}, resolve(body)...)...,
+	)
+	p.details.Refresh()
+
+	var warnings []string
+	if resolveErr != nil {
+		warnings = append(warnings, resolveErr.Error())
+	}
+	if len(unresolved) > 0 {
+		warnings = append(warnings, fmt.Sprintf(models.MsgUnresolvedVariables, strings.Join(unresolved, ", ")))
+	}
+	if len(warnings) == 0 {
+		p.warning.Hide()
+		return
+	}
+	p.warning.SetText(strings.Join(warnings, "\n"))
+	p.warning.Show()
+}
+
+// previewSegments splits resolved text into rich text segments, with the
+// placeholders left in it highlighted
+func previewSegments(resolved string) []widget.RichTextSegment {
+	plain := widget.RichTextStyle{Inline: true, TextStyle: fyne.TextStyle{Monospace: true}}
+	unresolved := widget.RichTextStyle{
+		Inline:    true,
+		ColorName: theme.ColorNameWarning,
+		TextStyle: fyne.TextStyle{Monospace: true, Bold: true},
+	}
+	dynamic := widget.RichTextStyle{
+		Inline:    true,
+		ColorName: theme.ColorNamePrimary,
+		TextStyle: fyne.TextStyle{Monospace: true},
+	}
+
+	var segments []widget.RichTextSegment
+	last := 0
+	for _, loc := range collection.Placeholders(resolved) {
+		if loc[0] > last {
+			segments = append(segments, &widget.TextSegment{Text: resolved[last:loc[0]], Style: plain})
+		}
+		placeholder := resolved[loc[0]:loc[1]]
+		style := unresolved
+		if collection.IsDynamicVariable(placeholder[2 : len(placeholder)-2]) {
+			style = dynamic
+		}
+		segments = append(segments, &widget.TextSegment{Text: placeholder, Style: style})
+		last = loc[1]
+	}
+	if last < len(resolved) || len(segments) == 0 {
+		segments = append(segments, &widget.TextSegment{Text: resolved[last:], Style: plain})
+	}
+	return segments
+}
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/romanitalian/GHOSTman/v2/internal/httpclient"
+	"github.com/romanitalian/GHOSTman/v2/internal/jsonview"
+	"github.com/romanitalian/GHOSTman/v2/models"
+)
+
+// maxImageSize is the largest width or height an image is shown with
+const maxImageSize = 600
+
+// bodyRenderer is a view of a response body
+type bodyRenderer struct {
+	label string
+	show  func()
+}
+
+// responseBodyView shows a response body the way its content type asks
+// for, with a choice of the views that fit, the raw text being the last
+type responseBodyView struct {
+	views     *widget.RadioGroup
+	renderers []bodyRenderer
+	entry     *widget.Entry
+	image     *canvas.Image
+	imageInfo *widget.Label
+	imageBox  *fyne.Container
+	jsonTree  *jsonTreeView
+
+	content fyne.CanvasObject
+}
+
+func newResponseBodyView() *responseBodyView {
+	v := &responseBodyView{
+		entry:     newResponseEntry(),
+		image:     canvas.NewImageFromResource(nil),
+		imageInfo: widget.NewLabel(""),
+		jsonTree:  newJSONTreeView(),
+	}
+	v.image.FillMode = canvas.ImageFillContain
+	v.imageBox = container.NewVBox(v.imageInfo, container.NewCenter(v.image))
+	v.imageBox.Hide()
+	v.jsonTree.content.Hide()
+	v.views = widget.NewRadioGroup(nil, func(selected string) {
+		for _, r := range v.renderers {
+			if r.label == selected {
+				r.show()
+			}
+		}
+	})
+	v.views.Horizontal = true
+	v.views.Required = true
+	v.views.Hide()
+	v.content = container.NewBorder(v.views, nil, nil, nil, container.NewStack(v.entry, v.imageBox, v.jsonTree.content))
+	return v
+}
+
+// setText shows text, e.g. an error, in place of a body
+func (v *responseBodyView) setText(text string) {
+	v.setRenderers(nil)
+	v.showText(text)
+}
+
+// update shows the body of resp with the views that fit its kind. The raw
+// bytes are shown as text, or as a hex dump for images.
+func (v *responseBodyView) update(resp *httpclient.Response) {
+	text := func(f func() string) func() {
+		return func() { v.showText(f()) }
+	}
+	raw := bodyRenderer{models.LabelRaw, text(resp.Text)}
+	hex := bodyRenderer{models.LabelViewHex, text(resp.HexDump)}
+
+	renderers := []bodyRenderer{raw}
+	switch resp.Kind() {
+	case httpclient.KindJSON:
+		renderers = []bodyRenderer{{models.LabelViewPretty, text(resp.PrettyBody)}, raw}
+		if root, err := jsonview.Parse(resp.Body); err == nil {
+			renderers = slices.Insert(renderers, 1, bodyRenderer{models.LabelViewTree, func() { v.showJSONTree(root) }})
+		}
+	case httpclient.KindXML:
+		if pretty, err := resp.PrettyXML(); err == nil {
+			renderers = []bodyRenderer{{models.LabelViewPretty, text(func() string { return pretty })}, raw}
+		}
+	case httpclient.KindHTML:
+		renderers = []bodyRenderer{{models.LabelViewSource, text(resp.Text)}, {models.LabelViewPreview, text(resp.HTMLText)}}
+	case httpclient.KindImage:
+		renderers = []bodyRenderer{{models.LabelViewImage, func() { v.showImage(resp) }}, hex}
+	case httpclient.KindBinary:
+		renderers = []bodyRenderer{hex, raw}
+	}
+	v.setRenderers(renderers)
+}
+
+// setRenderers offers renderers and shows the first one
+func (v *responseBodyView) setRenderers(renderers []bodyRenderer) {
+	v.renderers = renderers
+	labels := make([]string, len(renderers))
+	for i, r := range renderers {
+		labels[i] = r.label
+	}
+	v.views.Options = labels
+	v.views.Selected = ""
+	if len(renderers) > 1 {
+		v.views.Show()
+	} else {
+		v.views.Hide()
+	}
+	if len(renderers) > 0 {
+		// SetSelected calls the renderer
+		v.views.SetSelected(labels[0])
+	}
+	v.views.Refresh()
+}
+
+func (v *responseBodyView) showText(text string) {
+	v.imageBox.Hide()
+	v.jsonTree.content.Hide()
+	v.entry.SetText(text)
+	v.entry.Show()
+}
+
+func (v *responseBodyView) showImage(resp *httpclient.Response) {
+	config, format, err := resp.ImageConfig()
+	if err != nil {
+		v.showText(err.Error())
+		return
+	}
+	v.imageInfo.SetText(fmt.Sprintf(models.MsgImageInfo, strings.ToUpper(format), config.Width, config.Height))
+
+	// Scale large images down to fit, keeping their aspect ratio
+	width, height := float32(config.Width), float32(config.Height)
+	if scale := maxImageSize / max(width, height); scale < 1 {
+		width, height = width*scale, height*scale
+	}
+	v.image.Resource = fyne.NewStaticResource("response."+format, bytes.Clone(resp.Body))
+	v.image.SetMinSize(fyne.NewSize(width, height))
+	v.image.Refresh()
+
+	v.entry.Hide()
+	v.jsonTree.content.Hide()
+	v.imageBox.Show()
+}
+
+func (v *responseBodyView) showJSONTree(root *jsonview.Node) {
+	if v.jsonTree.root != root {
+		v.jsonTree.set(root)
+	}
+	v.entry.Hide()
+	v.imageBox.Hide()
+	v.jsonTree.content.Show()
+}
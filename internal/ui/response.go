@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/romanitalian/GHOSTman/v2/internal/httpclient"
+	"github.com/romanitalian/GHOSTman/v2/models"
+)
+
+// responseView shows a response in tabs: Body, Headers, Cookies, Timing,
+// Certificates and Raw, below a summary of its status, size and time, and above a filter of
+// JSON bodies
+type responseView struct {
+	// resp is the response shown, nil for none
+	resp      *httpclient.Response
+	summary   *widget.Label
+	actions   *fyne.Container
+	truncated *truncatedBanner
+	tabs      *container.AppTabs
+	body      *responseBodyView
+	headers   *widget.Form
+	cookies   *widget.Form
+	timing    *timingView
+	certs     *widget.Form
+	raw       *widget.Entry
+	filter    *responseFilter
+
+	content fyne.CanvasObject
+}
+
+// newResponseView returns the response view of the form formID, which keeps
+// the last filter of the form
+func newResponseView(formID string) *responseView {
+	v := &responseView{
+		summary: widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		body:    newResponseBodyView(),
+		headers: widget.NewForm(),
+		cookies: widget.NewForm(),
+		timing:  newTimingView(),
+		certs:   widget.NewForm(),
+		raw:     newResponseEntry(),
+		filter:  newResponseFilter(formID),
+	}
+	v.summary.Hide()
+	v.actions = newResponseActions(func() *httpclient.Response { return v.resp })
+	v.actions.Hide()
+	v.truncated = newTruncatedBanner(v.body)
+	v.tabs = container.NewAppTabs(
+		container.NewTabItem(models.LabelBody, v.body.content),
+		container.NewTabItem(models.LabelHeaders, v.headers),
+		container.NewTabItem(models.LabelCookies, v.cookies),
+		container.NewTabItem(models.LabelTiming, v.timing.content),
+		container.NewTabItem(models.LabelCertificates, v.certs),
+		container.NewTabItem(models.LabelRaw, v.raw),
+	)
+	v.content = container.NewVBox(container.NewBorder(nil, nil, nil, v.actions, v.summary), v.truncated.content, v.tabs, v.filter.content)
+	return v
+}
+
+// replace makes resp the response shown, removing the body file of the
+// response shown before
+func (v *responseView) replace(resp *httpclient.Response) {
+	if v.resp != nil && v.resp != resp {
+		v.resp.RemoveBodyFile()
+	}
+	v.resp = resp
+}
+
+// newResponseEntry returns a monospace entry showing a response
+func newResponseEntry() *widget.Entry {
+	entry := widget.NewMultiLineEntry()
+	entry.Wrapping = fyne.TextWrapWord
+	entry.TextStyle = fyne.TextStyle{
+		Bold:      true,
+		Monospace: true,
+	}
+	entry.SetMinRowsVisible(25)
+	return entry
+}
+
+// setText shows text, e.g. an error, in place of a response
+func (v *responseView) setText(text string) {
+	v.replace(nil)
+	v.summary.Hide()
+	v.actions.Hide()
+	v.body.setText(text)
+	v.raw.SetText("")
+	v.headers.Items = nil
+	v.headers.Refresh()
+	v.cookies.Items = nil
+	v.cookies.Refresh()
+	v.timing.update(httpclient.Timing{})
+	v.certs.Items = nil
+	v.certs.Refresh()
+	v.truncated.update(nil)
+	v.filter.update(nil)
+	v.tabs.SelectIndex(0)
+}
+
+// update shows resp with note, if any, below its summary
+func (v *responseView) update(resp *httpclient.Response, note string) {
+	v.replace(resp)
+	summary := fmt.Sprintf(models.MsgResponseSummary, resp.Proto, resp.Status,
+		formatSize(resp.Size()), formatDuration(resp.Timing.Total))
+	if note != "" {
+		summary += "\n" + note
+	}
+	v.summary.SetText(summary)
+	v.summary.Show()
+	v.actions.Show()
+
+	v.truncated.update(resp)
+	v.body.update(resp)
+	v.raw.SetText(strings.ToValidUTF8(resp.Raw(), "\uFFFD"))
+
+	v.headers.Items = nil
+	names := make([]string, 0, len(resp.Header))
+	for name := range resp.Header {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		for _, value := range resp.Header[name] {
+			v.headers.Append(name, newValueLabel(value))
+		}
+	}
+	v.headers.Refresh()
+
+	v.cookies.Items = nil
+	for _, c := range resp.Cookies {
+		v.cookies.Append(c.Name, newValueLabel(cookieText(c)))
+	}
+	if len(resp.Cookies) == 0 {
+		v.cookies.Append("", widget.NewLabel(models.MsgNoCookies))
+	}
+	v.cookies.Refresh()
+
+	v.timing.update(resp.Timing)
+	updateCertificates(v.certs, resp.TLS)
+	v.filter.update(resp)
+}
+
+// newValueLabel returns a selectable label of a header or cookie value
+func newValueLabel(value string) *widget.Label {
+	label := widget.NewLabel(value)
+	label.Wrapping = fyne.TextWrapBreak
+	label.Selectable = true
+	return label
+}
+
+// cookieText returns the value of c followed by its attributes
+func cookieText(c *http.Cookie) string {
+	parts := []string{c.Value}
+	if c.Domain != "" {
+		parts = append(parts, "Domain="+c.Domain)
+	}
+	if c.Path != "" {
+		parts = append(parts, "Path="+c.Path)
+	}
+	if !c.Expires.IsZero() {
+		parts = append(parts, "Expires="+c.Expires.UTC().Format(http.TimeFormat))
+	}
+	if c.MaxAge > 0 {
+		parts = append(parts, fmt.Sprintf("Max-Age=%d", c.MaxAge))
+	}
+	if c.Secure {
+		parts = append(parts, "Secure")
+	}
+	if c.HttpOnly {
+		parts = append(parts, "HttpOnly")
+	}
+	switch c.SameSite {
+	case http.SameSiteLaxMode:
+		parts = append(parts, "SameSite=Lax")
+	case http.SameSiteStrictMode:
+		parts = append(parts, "SameSite=Strict")
+	case http.SameSiteNoneMode:
+		parts = append(parts, "SameSite=None")
+	}
+	return strings.Join(parts, "; ")
+}
+
+// formatSize formats a size in bytes for display
+func formatSize(size int) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%d B", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	}
+}
@@ -0,0 +1,314 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/romanitalian/GHOSTman/v2/internal/httpclient"
+	"github.com/romanitalian/GHOSTman/v2/models"
+)
+
+// Preferences of the settings screen
+const (
+	preferenceTimeout          = "timeout" // seconds
+	preferenceFollowRedirects  = "followRedirects"
+	preferenceMaxRedirects     = "maxRedirects"
+	preferenceProxy            = "proxy"
+	preferenceNoProxy          = "noProxy"
+	preferenceVerifyTLS        = "verifyTLS"
+	preferenceMaxResponseSize  = "maxResponseSize" // bytes
+	preferenceKeepFullResponse = "keepFullResponse"
+
+	// preferenceRequestSettings prefixes the preference holding the settings
+	// a request overrides, followed by the form ID
+	preferenceRequestSettings = "requestSettings."
+)
+
+// Defaults of the settings not kept yet
+var (
+	defaultSettings        = httpclient.DefaultSettings()
+	defaultMaxRedirects    = 10
+	defaultMaxResponseSize = defaultSettings.MaxResponseSize
+)
+
+// loadSettings returns the client settings kept in p
+func loadSettings(p fyne.Preferences) httpclient.Settings {
+	return httpclient.Settings{
+		Timeout:            time.Duration(p.FloatWithFallback(preferenceTimeout, defaultSettings.Timeout.Seconds()) * float64(time.Second)),
+		DisableRedirects:   !p.BoolWithFallback(preferenceFollowRedirects, true),
+		MaxRedirects:       p.IntWithFallback(preferenceMaxRedirects, defaultMaxRedirects),
+		Proxy:              p.String(preferenceProxy),
+		NoProxy:            p.String(preferenceNoProxy),
+		InsecureSkipVerify: !p.BoolWithFallback(preferenceVerifyTLS, true),
+		MaxResponseSize:    int64(p.IntWithFallback(preferenceMaxResponseSize, int(defaultMaxResponseSize))),
+		KeepFullResponse:   p.Bool(preferenceKeepFullResponse),
+		TLS:                loadTLSHosts(p),
+	}
+}
+
+// ShowSettings shows the settings screen in w. The settings are kept in the
+// preferences of the application and apply to the requests sent next.
+func ShowSettings(w fyne.Window) {
+	p := fyne.CurrentApp().Preferences()
+	s := loadSettings(p)
+
+	timeout := widget.NewEntry()
+	timeout.SetText(strconv.FormatFloat(s.Timeout.Seconds(), 'f', -1, 64))
+	timeout.Validator = func(text string) error {
+		_, err := parseSeconds(text)
+		return err
+	}
+	follow := widget.NewCheck("", nil)
+	follow.SetChecked(!s.DisableRedirects)
+	maxRedirects := widget.NewEntry()
+	maxRedirects.SetText(strconv.Itoa(s.MaxRedirects))
+	maxRedirects.Validator = func(text string) error {
+		_, err := parseCount(text)
+		return err
+	}
+	proxy := widget.NewEntry()
+	proxy.SetText(s.Proxy)
+	proxy.SetPlaceHolder(models.PlaceholderProxy)
+	noProxy := widget.NewEntry()
+	noProxy.SetText(s.NoProxy)
+	noProxy.SetPlaceHolder(models.PlaceholderNoProxy)
+	verify := widget.NewCheck("", nil)
+	verify.SetChecked(!s.InsecureSkipVerify)
+	maxSize := widget.NewEntry()
+	maxSize.SetText(strconv.FormatInt(s.MaxResponseSize, 10))
+	maxSize.Validator = maxRedirects.Validator
+	keep := widget.NewCheck("", nil)
+	keep.SetChecked(s.KeepFullResponse)
+	tlsHosts := newTLSHostsEditor(s.TLS)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(models.LabelTimeout, timeout),
+		widget.NewFormItem(models.LabelFollowRedirects, follow),
+		widget.NewFormItem(models.LabelMaxRedirects, maxRedirects),
+		widget.NewFormItem(models.LabelProxy, proxy),
+		widget.NewFormItem(models.LabelNoProxy, noProxy),
+		widget.NewFormItem(models.LabelVerifyTLS, verify),
+		widget.NewFormItem(models.LabelClientCerts, tlsHosts.content),
+		widget.NewFormItem(models.LabelMaxResponseSize, maxSize),
+		widget.NewFormItem(models.LabelKeepFullResponse, keep),
+	}
+	d := dialog.NewForm(models.LabelSettings, models.LabelSave, models.LabelCancel, items, func(ok bool) {
+		if !ok {
+			return
+		}
+		// the form is only confirmed with valid entries
+		seconds, _ := parseSeconds(timeout.Text)
+		redirects, _ := parseCount(maxRedirects.Text)
+		size, _ := parseCount(maxSize.Text)
+		p.SetFloat(preferenceTimeout, seconds)
+		p.SetBool(preferenceFollowRedirects, follow.Checked)
+		p.SetInt(preferenceMaxRedirects, redirects)
+		p.SetString(preferenceProxy, strings.TrimSpace(proxy.Text))
+		p.SetString(preferenceNoProxy, strings.TrimSpace(noProxy.Text))
+		p.SetBool(preferenceVerifyTLS, verify.Checked)
+		saveTLSHosts(p, tlsHosts.hosts())
+		p.SetInt(preferenceMaxResponseSize, size)
+		p.SetBool(preferenceKeepFullResponse, keep.Checked)
+	}, w)
+	d.Resize(fyne.NewSize(520, 0))
+	d.Show()
+}
+
+// loadPreferenceJSON decodes the JSON of the preference key into v. An
+// invalid value is logged and leaves v unchanged.
+func loadPreferenceJSON(p fyne.Preferences, key string, v any) {
+	data := p.String(key)
+	if data == "" {
+		return
+	}
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		log.Warn().Err(err).Str("key", key).Msg(models.LogInvalidPreference)
+	}
+}
+
+// parseSeconds parses a timeout in seconds, 0 for none
+func parseSeconds(text string) (float64, error) {
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil || seconds < 0 {
+		return 0, errors.New(models.ErrInvalidTimeout)
+	}
+	return seconds, nil
+}
+
+// parseCount parses a count greater than 0
+func parseCount(text string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || n <= 0 {
+		return 0, errors.New(models.ErrInvalidCount)
+	}
+	return n, nil
+}
+
+// Choices of the settings a request overrides and the values they are kept
+// as, the first is the default
+var (
+	redirectChoices = []string{models.LabelDefault, models.LabelFollow, models.LabelDontFollow}
+	redirectValues  = []string{"", "follow", "none"}
+	verifyChoices   = []string{models.LabelDefault, models.LabelVerify, models.LabelDontVerify}
+	verifyValues    = []string{"", "verify", "skip"}
+)
+
+// choiceValue returns the value of the choice selected
+func choiceValue(choices, values []string, selected string) string {
+	if i := slices.Index(choices, selected); i >= 0 {
+		return values[i]
+	}
+	return ""
+}
+
+// choiceOf returns the choice of value, the default for an unknown value
+func choiceOf(choices, values []string, value string) string {
+	return choices[max(slices.Index(values, value), 0)]
+}
+
+// settingsOverride is the settings a request overrides, as edited. Empty
+// values keep the settings of the settings screen. Redirects is "follow" or
+// "none", VerifyTLS is "verify" or "skip".
+type settingsOverride struct {
+	Timeout      string `json:"timeout,omitempty"`
+	Redirects    string `json:"redirects,omitempty"`
+	MaxRedirects string `json:"maxRedirects,omitempty"`
+	Proxy        string `json:"proxy,omitempty"`
+	NoProxy      string `json:"noProxy,omitempty"`
+	VerifyTLS    string `json:"verifyTLS,omitempty"`
+}
+
+// apply returns s with the overridden settings
+func (o settingsOverride) apply(s httpclient.Settings) (httpclient.Settings, error) {
+	if o.Timeout != "" {
+		seconds, err := parseSeconds(o.Timeout)
+		if err != nil {
+			return s, err
+		}
+		s.Timeout = time.Duration(seconds * float64(time.Second))
+	}
+	switch o.Redirects {
+	case "follow":
+		s.DisableRedirects = false
+	case "none":
+		s.DisableRedirects = true
+	}
+	if o.MaxRedirects != "" {
+		n, err := parseCount(o.MaxRedirects)
+		if err != nil {
+			return s, err
+		}
+		s.MaxRedirects = n
+	}
+	if o.Proxy != "" {
+		s.Proxy = o.Proxy
+	}
+	if o.NoProxy != "" {
+		s.NoProxy = o.NoProxy
+	}
+	switch o.VerifyTLS {
+	case "verify":
+		s.InsecureSkipVerify = false
+	case "skip":
+		s.InsecureSkipVerify = true
+	}
+	return s, nil
+}
+
+// requestSettingsEditor edits the settings a request overrides, kept in the
+// preferences by form ID
+type requestSettingsEditor struct {
+	timeout      *widget.Entry
+	redirects    *widget.Select
+	maxRedirects *widget.Entry
+	proxy        *widget.Entry
+	noProxy      *widget.Entry
+	verifyTLS    *widget.Select
+	// key is the preference of the overrides, empty when they are not kept
+	key string
+
+	content fyne.CanvasObject
+}
+
+func newRequestSettingsEditor(formID string) *requestSettingsEditor {
+	e := &requestSettingsEditor{
+		timeout:      widget.NewEntry(),
+		redirects:    widget.NewSelect(redirectChoices, nil),
+		maxRedirects: widget.NewEntry(),
+		proxy:        widget.NewEntry(),
+		noProxy:      widget.NewEntry(),
+		verifyTLS:    widget.NewSelect(verifyChoices, nil),
+	}
+	var o settingsOverride
+	if formID != "" {
+		e.key = preferenceRequestSettings + formID
+		loadPreferenceJSON(fyne.CurrentApp().Preferences(), e.key, &o)
+	}
+	e.timeout.SetText(o.Timeout)
+	e.redirects.SetSelected(choiceOf(redirectChoices, redirectValues, o.Redirects))
+	e.maxRedirects.SetText(o.MaxRedirects)
+	e.proxy.SetText(o.Proxy)
+	e.noProxy.SetText(o.NoProxy)
+	e.verifyTLS.SetSelected(choiceOf(verifyChoices, verifyValues, o.VerifyTLS))
+
+	for _, entry := range []*widget.Entry{e.timeout, e.maxRedirects, e.proxy, e.noProxy} {
+		entry.SetPlaceHolder(models.LabelDefault)
+		entry.OnChanged = func(string) { e.save() }
+	}
+	e.redirects.OnChanged = func(string) { e.save() }
+	e.verifyTLS.OnChanged = func(string) { e.save() }
+
+	form := widget.NewForm(
+		widget.NewFormItem(models.LabelTimeout, e.timeout),
+		widget.NewFormItem(models.LabelRedirects, e.redirects),
+		widget.NewFormItem(models.LabelMaxRedirects, e.maxRedirects),
+		widget.NewFormItem(models.LabelProxy, e.proxy),
+		widget.NewFormItem(models.LabelNoProxy, e.noProxy),
+		widget.NewFormItem(models.LabelTLS, e.verifyTLS),
+	)
+	e.content = widget.NewAccordion(widget.NewAccordionItem(models.LabelRequestSettings, container.NewPadded(form)))
+	return e
+}
+
+// override returns the overrides as edited
+func (e *requestSettingsEditor) override() settingsOverride {
+	return settingsOverride{
+		Timeout:      strings.TrimSpace(e.timeout.Text),
+		Redirects:    choiceValue(redirectChoices, redirectValues, e.redirects.Selected),
+		MaxRedirects: strings.TrimSpace(e.maxRedirects.Text),
+		Proxy:        strings.TrimSpace(e.proxy.Text),
+		NoProxy:      strings.TrimSpace(e.noProxy.Text),
+		VerifyTLS:    choiceValue(verifyChoices, verifyValues, e.verifyTLS.Selected),
+	}
+}
+
+// save keeps the overrides in the preferences
+func (e *requestSettingsEditor) save() {
+	if e.key == "" {
+		return
+	}
+	data, _ := json.Marshal(e.override())
+	fyne.CurrentApp().Preferences().SetString(e.key, string(data))
+}
+
+// settings returns the settings of the settings screen with the overrides
+// of the request
+func (e *requestSettingsEditor) settings() (httpclient.Settings, error) {
+	s, err := e.override().apply(loadSettings(fyne.CurrentApp().Preferences()))
+	if err != nil {
+		return s, fmt.Errorf("%s: %w", models.LabelRequestSettings, err)
+	}
+	return s, nil
+}
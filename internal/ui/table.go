@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/romanitalian/GHOSTman/v2/models"
+)
+
+// fileTypes are the value types of a row in a table with files
+var fileTypes = []string{models.LabelText, models.LabelFile}
+
+// keyValueRow is a row of a keyValueTable
+type keyValueRow struct {
+	Key      string
+	Value    string
+	Disabled bool
+	// File marks a row whose value is a file path, in tables with files
+	File bool
+}
+
+// keyValueTable edits a list of key/value rows, each with a check box to
+// disable it without removing it
+type keyValueTable struct {
+	files     bool
+	fixedKeys bool
+	// keyOptions are suggested while a key is typed
+	keyOptions []string
+	rows       []*keyValueRowWidgets
+	box        *fyne.Container
+	addBtn     *widget.Button
+
+	// OnChanged is called after every edit of the rows
+	OnChanged func()
+
+	content fyne.CanvasObject
+}
+
+// keyValueRowWidgets are the widgets editing a row
+type keyValueRowWidgets struct {
+	enabled  *widget.Check
+	key      *widget.Entry
+	value    *widget.Entry
+	fileType *widget.Select
+	browse   *widget.Button
+	content  fyne.CanvasObject
+}
+
+// newKeyValueTable returns an empty table. With files set every row has a
+// type select to make its value a file path.
+func newKeyValueTable(files bool) *keyValueTable {
+	t := &keyValueTable{files: files, box: container.NewVBox()}
+	t.addBtn = widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() {
+		t.addRow(keyValueRow{})
+		t.changed()
+	})
+	t.content = container.NewVBox(t.box, container.NewHBox(t.addBtn))
+	return t
+}
+
+// CompleteKeys suggests the options matching the typed key in the key
+// entries of the rows set afterwards
+func (t *keyValueTable) CompleteKeys(options []string) {
+	t.keyOptions = options
+}
+
+// FixKeys makes the rows set afterwards editable in their value only. The
+// rows can be neither added, removed nor disabled.
+func (t *keyValueTable) FixKeys() {
+	t.fixedKeys = true
+	t.addBtn.Hide()
+}
+
+// SetRows replaces the rows of the table
+func (t *keyValueTable) SetRows(rows []keyValueRow) {
+	t.rows = nil
+	t.box.RemoveAll()
+	for _, row := range rows {
+		t.addRow(row)
+	}
+}
+
+// Rows returns the rows as edited
+func (t *keyValueTable) Rows() []keyValueRow {
+	rows := make([]keyValueRow, len(t.rows))
+	for i, w := range t.rows {
+		rows[i] = keyValueRow{
+			Key:      w.key.Text,
+			Value:    w.value.Text,
+			Disabled: !w.enabled.Checked,
+			File:     w.fileType != nil && w.fileType.Selected == models.LabelFile,
+		}
+	}
+	return rows
+}
+
+func (t *keyValueTable) changed() {
+	if t.OnChanged != nil {
+		t.OnChanged()
+	}
+}
+
+func (t *keyValueTable) addRow(row keyValueRow) {
+	w := &keyValueRowWidgets{
+		enabled: widget.NewCheck("", nil),
+		key:     widget.NewEntry(),
+		value:   widget.NewEntry(),
+	}
+	var keyObject fyne.CanvasObject = w.key
+	var completion *widget.SelectEntry
+	if len(t.keyOptions) > 0 {
+		completion = widget.NewSelectEntry(t.keyOptions)
+		w.key, keyObject = &completion.Entry, completion
+	}
+	w.enabled.SetChecked(!row.Disabled)
+	w.key.SetPlaceHolder(models.LabelKey)
+	w.key.SetText(row.Key)
+	w.value.SetPlaceHolder(models.LabelValue)
+	w.value.SetText(row.Value)
+
+	actions := container.NewHBox()
+	if t.files {
+		w.browse = widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+			dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+				if err != nil || reader == nil {
+					return
+				}
+				defer reader.Close()
+				w.value.SetText(reader.URI().Path())
+			}, windowFor(t.content))
+		})
+		showBrowse := func(selected string) {
+			if selected == models.LabelFile {
+				w.browse.Show()
+			} else {
+				w.browse.Hide()
+			}
+		}
+		w.fileType = widget.NewSelect(fileTypes, nil)
+		w.fileType.SetSelected(models.LabelText)
+		if row.File {
+			w.fileType.SetSelected(models.LabelFile)
+		}
+		showBrowse(w.fileType.Selected)
+		w.fileType.OnChanged = func(selected string) {
+			showBrowse(selected)
+			t.changed()
+		}
+		actions.Add(w.fileType)
+		actions.Add(w.browse)
+	}
+	if t.fixedKeys {
+		w.key.Disable()
+		w.enabled.Hide()
+	} else {
+		actions.Add(widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+			t.removeRow(w)
+			t.changed()
+		}))
+	}
+
+	onChanged := func(string) { t.changed() }
+	w.key.OnChanged = onChanged
+	if completion != nil {
+		w.key.OnChanged = func(key string) {
+			completion.SetOptions(matchingOptions(t.keyOptions, key))
+			t.changed()
+		}
+	}
+	w.value.OnChanged = onChanged
+	w.enabled.OnChanged = func(bool) { t.changed() }
+
+	w.content = container.NewBorder(nil, nil, w.enabled, actions, container.NewGridWithColumns(2, keyObject, w.value))
+	t.rows = append(t.rows, w)
+	t.box.Add(w.content)
+}
+
+func (t *keyValueTable) removeRow(w *keyValueRowWidgets) {
+	for i, row := range t.rows {
+		if row == w {
+			t.rows = append(t.rows[:i], t.rows[i+1:]...)
+			break
+		}
+	}
+	t.box.Remove(w.content)
+}
+
+// matchingOptions returns the options starting with prefix, ignoring case,
+// all options when none does
+func matchingOptions(options []string, prefix string) []string {
+	var matching []string
+	for _, option := range options {
+		if len(option) >= len(prefix) && strings.EqualFold(option[:len(prefix)], prefix) {
+			matching = append(matching, option)
+		}
+	}
+	if len(matching) == 0 {
+		return options
+	}
+	return matching
+}
+
+// windowFor returns the window showing obj, the first window when obj is
+// not shown yet
+func windowFor(obj fyne.CanvasObject) fyne.Window {
+	windows := fyne.CurrentApp().Driver().AllWindows()
+	c := fyne.CurrentApp().Driver().CanvasForObject(obj)
+	for _, w := range windows {
+		if w.Canvas() == c {
+			return w
+		}
+	}
+	if len(windows) > 0 {
+		return windows[0]
+	}
+	return nil
+}
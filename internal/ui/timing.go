@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/romanitalian/GHOSTman/v2/internal/httpclient"
+	"github.com/romanitalian/GHOSTman/v2/models"
+)
+
+// timingView shows the timing of a request as a waterfall, a bar per phase
+// placed on the time line of the whole request
+type timingView struct {
+	rows    *widget.Form
+	content fyne.CanvasObject
+}
+
+func newTimingView() *timingView {
+	v := &timingView{rows: widget.NewForm()}
+	v.content = v.rows
+	return v
+}
+
+// update shows timing, nothing for an empty timing
+func (v *timingView) update(timing httpclient.Timing) {
+	v.rows.Items = nil
+	defer v.rows.Refresh()
+	if timing.Total == 0 {
+		return
+	}
+
+	phases := []struct {
+		label string
+		phase httpclient.Phase
+	}{
+		{models.LabelTimingDNS, timing.DNS},
+		{models.LabelTimingConnect, timing.Connect},
+		{models.LabelTimingTLS, timing.TLSHandshake},
+		{models.LabelTimingWait, timing.Wait},
+		{models.LabelTimingDownload, timing.Download},
+	}
+
+	for _, p := range phases {
+		if p.phase.Duration() <= 0 && p.label != models.LabelTimingWait {
+			continue
+		}
+		bar := canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+		v.rows.Append(p.label, container.NewBorder(nil, nil, nil, widget.NewLabel(formatDuration(p.phase.Duration())),
+			container.New(&waterfallLayout{phase: p.phase, total: timing.Total}, bar)))
+	}
+	total := models.LabelTimingTotal
+	if timing.ReusedConn {
+		total += " " + models.MsgReusedConnection
+	}
+	v.rows.Append(total, widget.NewLabelWithStyle(formatDuration(timing.Total), fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}))
+}
+
+// formatDuration formats d rounded for display
+func formatDuration(d time.Duration) string {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond).String()
+	}
+	return d.Round(time.Millisecond).String()
+}
+
+// waterfallLayout places its object in the part of the width that phase
+// takes of total
+type waterfallLayout struct {
+	phase httpclient.Phase
+	total time.Duration
+}
+
+func (l *waterfallLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	if l.total <= 0 {
+		return
+	}
+	start := float32(l.phase.Start) / float32(l.total) * size.Width
+	width := float32(l.phase.Duration()) / float32(l.total) * size.Width
+	height := size.Height / 2
+	for _, o := range objects {
+		o.Move(fyne.NewPos(start, (size.Height-height)/2))
+		o.Resize(fyne.NewSize(max(width, 1), height))
+	}
+}
+
+func (l *waterfallLayout) MinSize([]fyne.CanvasObject) fyne.Size {
+	return fyne.NewSize(200, theme.TextSize())
+}
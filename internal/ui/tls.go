@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/romanitalian/GHOSTman/v2/internal/httpclient"
+	"github.com/romanitalian/GHOSTman/v2/models"
+)
+
+// preferenceTLSHosts is the preference holding the TLS settings of hosts as
+// JSON
+const preferenceTLSHosts = "tlsHosts"
+
+// storedTLSHost is the TLS settings of a host kept in the preferences,
+// without the password of its certificate file
+type storedTLSHost struct {
+	Host     string `json:"host"`
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+	CAFile   string `json:"caFile,omitempty"`
+}
+
+// tlsPasswords are the passwords of the certificate files entered in the
+// settings screen, by host and file. They are asked again in every session
+// rather than written to the preferences.
+var tlsPasswords struct {
+	sync.Mutex
+	m map[storedTLSHost]string
+}
+
+// loadTLSHosts returns the TLS settings of hosts kept in p, with the
+// passwords entered in this session
+func loadTLSHosts(p fyne.Preferences) []httpclient.HostTLS {
+	var stored []storedTLSHost
+	loadPreferenceJSON(p, preferenceTLSHosts, &stored)
+	tlsPasswords.Lock()
+	defer tlsPasswords.Unlock()
+	hosts := make([]httpclient.HostTLS, len(stored))
+	for i, h := range stored {
+		hosts[i] = httpclient.HostTLS{
+			Host:     h.Host,
+			CertFile: h.CertFile,
+			KeyFile:  h.KeyFile,
+			Password: tlsPasswords.m[h],
+			CAFile:   h.CAFile,
+		}
+	}
+	return hosts
+}
+
+// saveTLSHosts keeps hosts in p and their passwords for this session
+func saveTLSHosts(p fyne.Preferences, hosts []httpclient.HostTLS) {
+	tlsPasswords.Lock()
+	defer tlsPasswords.Unlock()
+	tlsPasswords.m = make(map[storedTLSHost]string)
+	stored := make([]storedTLSHost, len(hosts))
+	for i, h := range hosts {
+		stored[i] = storedTLSHost{Host: h.Host, CertFile: h.CertFile, KeyFile: h.KeyFile, CAFile: h.CAFile}
+		if h.Password != "" {
+			tlsPasswords.m[stored[i]] = h.Password
+		}
+	}
+	data, _ := json.Marshal(stored)
+	p.SetString(preferenceTLSHosts, string(data))
+}
+
+// tlsHostsEditor edits the client certificates and CA certificates of
+// hosts, a group of entries per host
+type tlsHostsEditor struct {
+	rows []*tlsHostRow
+	box  *fyne.Container
+
+	content fyne.CanvasObject
+}
+
+// tlsHostRow are the entries of a host
+type tlsHostRow struct {
+	host     *widget.Entry
+	certFile *widget.Entry
+	keyFile  *widget.Entry
+	password *widget.Entry
+	caFile   *widget.Entry
+	content  fyne.CanvasObject
+}
+
+func newTLSHostsEditor(hosts []httpclient.HostTLS) *tlsHostsEditor {
+	e := &tlsHostsEditor{box: container.NewVBox()}
+	for _, h := range hosts {
+		e.addRow(h)
+	}
+	add := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() {
+		e.addRow(httpclient.HostTLS{})
+	})
+	e.content = container.NewVBox(e.box, container.NewHBox(add))
+	return e
+}
+
+func (e *tlsHostsEditor) addRow(h httpclient.HostTLS) {
+	r := &tlsHostRow{
+		host:     widget.NewEntry(),
+		certFile: widget.NewEntry(),
+		keyFile:  widget.NewEntry(),
+		password: widget.NewPasswordEntry(),
+		caFile:   widget.NewEntry(),
+	}
+	r.host.SetPlaceHolder(models.PlaceholderTLSHost)
+	r.host.SetText(h.Host)
+	r.certFile.SetPlaceHolder(models.PlaceholderCertFile)
+	r.certFile.SetText(h.CertFile)
+	r.keyFile.SetPlaceHolder(models.PlaceholderKeyFile)
+	r.keyFile.SetText(h.KeyFile)
+	r.password.SetPlaceHolder(models.PlaceholderCertPassword)
+	r.password.SetText(h.Password)
+	r.caFile.SetPlaceHolder(models.PlaceholderCAFile)
+	r.caFile.SetText(h.CAFile)
+
+	remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		e.removeRow(r)
+	})
+	r.content = container.NewVBox(
+		container.NewBorder(nil, nil, nil, remove, r.host),
+		e.fileEntry(r.certFile),
+		e.fileEntry(r.keyFile),
+		r.password,
+		e.fileEntry(r.caFile),
+		widget.NewSeparator(),
+	)
+	e.rows = append(e.rows, r)
+	e.box.Add(r.content)
+}
+
+// fileEntry returns entry with a button choosing its file
+func (e *tlsHostsEditor) fileEntry(entry *widget.Entry) fyne.CanvasObject {
+	browse := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			defer reader.Close()
+			entry.SetText(reader.URI().Path())
+		}, windowFor(e.content))
+	})
+	return container.NewBorder(nil, nil, nil, browse, entry)
+}
+
+func (e *tlsHostsEditor) removeRow(r *tlsHostRow) {
+	for i, row := range e.rows {
+		if row == r {
+			e.rows = append(e.rows[:i], e.rows[i+1:]...)
+			break
+		}
+	}
+	e.box.Remove(r.content)
+}
+
+// hosts returns the TLS settings of the hosts as edited, without the hosts
+// left empty
+func (e *tlsHostsEditor) hosts() []httpclient.HostTLS {
+	var hosts []httpclient.HostTLS
+	for _, r := range e.rows {
+		h := httpclient.HostTLS{
+			Host:     strings.TrimSpace(r.host.Text),
+			CertFile: strings.TrimSpace(r.certFile.Text),
+			KeyFile:  strings.TrimSpace(r.keyFile.Text),
+			Password: r.password.Text,
+			CAFile:   strings.TrimSpace(r.caFile.Text),
+		}
+		if h.Host != "" {
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/romanitalian/GHOSTman/v2/internal/httpclient"
+	"github.com/romanitalian/GHOSTman/v2/models"
+)
+
+// truncatedBanner tells that a response body is truncated. When the whole
+// body is kept in a temporary file, it can be opened or shown chunk by chunk
+// in the body view, and the save action of the response saves all of it.
+type truncatedBanner struct {
+	resp       *httpclient.Response
+	body       *responseBodyView
+	label      *widget.Label
+	chunk      int
+	chunkLabel *widget.Label
+	file       *fyne.Container
+
+	content fyne.CanvasObject
+}
+
+func newTruncatedBanner(body *responseBodyView) *truncatedBanner {
+	b := &truncatedBanner{
+		body:       body,
+		label:      widget.NewLabel(""),
+		chunkLabel: widget.NewLabel(""),
+	}
+	b.label.Wrapping = fyne.TextWrapWord
+	open := widget.NewButtonWithIcon(models.LabelOpenBody, theme.FileIcon(), b.open)
+	prev := widget.NewButtonWithIcon("", theme.NavigateBackIcon(), func() { b.showChunk(b.chunk - 1) })
+	next := widget.NewButtonWithIcon("", theme.NavigateNextIcon(), func() { b.showChunk(b.chunk + 1) })
+	b.file = container.NewHBox(open, prev, b.chunkLabel, next)
+	b.content = container.NewBorder(nil, nil, nil, b.file, b.label)
+	b.content.Hide()
+	return b
+}
+
+// update shows the banner when the body of resp is truncated, resp is nil
+// for no response
+func (b *truncatedBanner) update(resp *httpclient.Response) {
+	b.resp, b.chunk = resp, 0
+	if resp == nil || !resp.Truncated() {
+		b.content.Hide()
+		return
+	}
+	if resp.FullSize < 0 {
+		b.label.SetText(fmt.Sprintf(models.MsgBodyTruncated, len(resp.Body)))
+	} else {
+		b.label.SetText(fmt.Sprintf(models.MsgBodyTruncatedOf, len(resp.Body), resp.FullSize))
+	}
+	if resp.BodyFile != "" {
+		b.chunkLabel.SetText(fmt.Sprintf(models.MsgBodyChunk, 1, resp.Chunks()))
+		b.file.Show()
+	} else {
+		b.file.Hide()
+	}
+	b.content.Show()
+}
+
+// showChunk shows the chunk i of the whole body, the first one with the
+// views of the body
+func (b *truncatedBanner) showChunk(i int) {
+	if b.resp == nil || i < 0 || i >= b.resp.Chunks() {
+		return
+	}
+	b.chunk = i
+	b.chunkLabel.SetText(fmt.Sprintf(models.MsgBodyChunk, i+1, b.resp.Chunks()))
+	if i == 0 {
+		b.body.update(b.resp)
+		return
+	}
+	text, err := b.resp.Chunk(i)
+	if err != nil {
+		text = err.Error()
+	}
+	b.body.setText(text)
+}
+
+// open opens the whole body with the application of the system
+func (b *truncatedBanner) open() {
+	if b.resp == nil || b.resp.BodyFile == "" {
+		return
+	}
+	u := &url.URL{Scheme: "file", Path: b.resp.BodyFile}
+	if err := fyne.CurrentApp().OpenURL(u); err != nil {
+		dialog.ShowError(err, windowFor(b.content))
+	}
+}
@@ -1,115 +1,277 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"net/http"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
 
-	"github.com/romanitalian/GHOSTman/internal/collection"
-	"github.com/romanitalian/GHOSTman/internal/httpclient"
+	"github.com/romanitalian/GHOSTman/v2/internal/collection"
+	"github.com/romanitalian/GHOSTman/v2/internal/httpclient"
+	"github.com/romanitalian/GHOSTman/v2/models"
 )
 
-type Form struct {
-	ID    string
-	Title string
-	Intro string
-	Form  fyne.CanvasObject
+var httpMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS", "CONNECT", "TRACE"}
+
+// SaveFunc persists an edited request
+type SaveFunc func(*collection.Request) error
+
+// VariablesFunc returns the variables currently in effect. It is called
+// whenever the form resolves {{var}} placeholders, so changed variables
+// apply to forms that are already built.
+type VariablesFunc func() map[string]string
+
+// EnvironmentFunc returns the name of the active environment, empty when
+// none is
+type EnvironmentFunc func() string
+
+// RequestForm is a built request form and the actions the application
+// triggers from outside of it
+type RequestForm struct {
+	Content fyne.CanvasObject
+	// Save persists the edited request, e.g. from a menu item or shortcut
+	Save func()
+	// Refresh resolves the preview again after the variables changed
+	Refresh func()
 }
 
-func CreateForm(item struct {
-	Name    string `json:"name"`
-	Request struct {
-		Method      string `json:"method"`
-		Description string `json:"description"`
-		Header      []struct {
-			Key   string `json:"key"`
-			Value string `json:"value"`
-		} `json:"header"`
-		Body struct {
-			Mode string `json:"mode"`
-			Raw  string `json:"raw"`
-		} `json:"body"`
-		URL struct {
-			Raw  string   `json:"raw"`
-			Host []string `json:"host"`
-			Path []string `json:"path"`
-		} `json:"url"`
-	} `json:"request"`
-}, vars map[string]string) fyne.CanvasObject {
+// CreateForm builds the request form for a collection item. The editors keep
+// the {{var}} placeholders; they are resolved with vars when the request is
+// sent and in the preview below the editors. environment names the
+// environment vars come from, e.g. to keep OAuth2 tokens apart. inherited is the auth of the
+// closest folder or the collection that sets one, used while the request has
+// no auth of its own. save persists the edited request. formID identifies the
+// form across runs, e.g. to keep its settings and last response filter.
+func CreateForm(formID string, item collection.Item, inherited *collection.Auth, vars VariablesFunc, environment EnvironmentFunc, save SaveFunc) *RequestForm {
+	request := item.Request
+	if request == nil {
+		request = &collection.Request{}
+	}
+	// Create form fields
 	frm := &widget.Form{}
 
-	urlEntry := widget.NewEntry()
-	urlEntry.SetText(collection.SubstituteVariables(item.Request.URL.Raw, vars))
-	frm.Append("URL", urlEntry)
+	// Add request info fields
+	urlEdit := newURLEditor(request.URL)
+	frm.Append(models.LabelURL, urlEdit.entry)
+	frm.Append(models.LabelQueryParams, urlEdit.query.content)
+	frm.Append(models.LabelPathVariables, urlEdit.variables.content)
 
-	methodSelect := widget.NewSelect([]string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS", "CONNECT", "TRACE"}, func(value string) {})
-	methodSelect.SetSelected(item.Request.Method)
-	frm.Append("Method", methodSelect)
+	methodSelect := widget.NewSelect(httpMethods, func(value string) {})
+	methodSelect.SetSelected(request.Method)
+	frm.Append(models.LabelMethod, methodSelect)
 
-	var headersText strings.Builder
-	for _, h := range item.Request.Header {
-		headersText.WriteString(fmt.Sprintf("%s: %s\n", h.Key, collection.SubstituteVariables(h.Value, vars)))
-	}
-	hdrsEntry := widget.NewMultiLineEntry()
-	hdrsEntry.SetText(headersText.String())
-	frm.Append("Headers", hdrsEntry)
-
-	bodyEntry := widget.NewMultiLineEntry()
-	bodyEntry.SetText(collection.SubstituteVariables(item.Request.Body.Raw, vars))
-	lines := strings.Count(item.Request.Body.Raw, "\n") + 1
-	bodyEntry.SetMinRowsVisible(lines)
-	frm.Append("Body", bodyEntry)
-
-	textRS := widget.NewMultiLineEntry()
-	textRS.Wrapping = fyne.TextWrapWord
-	textRS.TextStyle = fyne.TextStyle{
-		Bold:      true,
-		Monospace: true,
+	headerEdit := newHeaderEditor(request.Header)
+	frm.Append(models.LabelHeaders, headerEdit.table.content)
+
+	authEdit := newAuthEditor(request.Auth, inherited)
+	frm.Append(models.LabelAuth, authEdit.content)
+
+	bodyEdit := newBodyEditor(request.Body)
+	frm.Append(models.LabelBody, bodyEdit.content)
+
+	settingsEdit := newRequestSettingsEditor(formID)
+	frm.Append(models.LabelSettings, settingsEdit.content)
+
+	// Show the request as it will be sent
+	preview := newRequestPreview()
+	refreshPreview := func(string) {
+		preview.update(vars(), urlEdit.entry.Text, headerEdit.text(), bodyEdit.text())
 	}
-	textRS.Resize(fyne.NewSize(200, 200))
-	textRS.SetMinRowsVisible(25)
+	urlEdit.OnChanged = func() { refreshPreview("") }
+	headerEdit.table.OnChanged = func() { refreshPreview("") }
+	bodyEdit.OnChanged = func() { refreshPreview("") }
+	refreshPreview("")
+	frm.Append(models.LabelPreview, preview.content)
 
+	// Create response pane
+	response := newResponseView(formID)
+
+	// Create progress bar
 	progressBar := widget.NewProgressBarInfinite()
 	progressBar.Hide()
+	elapsedLabel := widget.NewLabel("")
+	elapsedLabel.Hide()
 
-	submitBtn := widget.NewButton("Send", func() {
-		textRS.SetText("")
+	// Add submit button, it cancels the request while it is in flight
+	var submitBtn *widget.Button
+	var cancel context.CancelFunc
+	finish := func(text string) {
+		progressBar.Hide()
+		progressBar.Refresh()
+		elapsedLabel.Hide()
+		response.setText(text)
+	}
+	submitBtn = widget.NewButton(models.LabelSend, func() {
+		if cancel != nil {
+			cancel()
+			return
+		}
+
+		// Clear response and show progress
+		response.setText("")
 		progressBar.Show()
 		progressBar.Refresh()
 
-		rq, err := httpclient.NewRequest(methodSelect.Selected, urlEntry.Text, bodyEntry.Text, hdrsEntry.Text)
+		// Resolve variables at send time, dynamic variables get fresh values
+		vs := vars()
+		refreshPreview("")
+		resolve := func(s string) (string, error) {
+			resolved, _, err := collection.ResolveVariables(s, vs)
+			return collection.DefaultDynamicVariables.Substitute(resolved), err
+		}
+		resolvedURL, err := resolve(urlEdit.entry.Text)
+		if err != nil {
+			finish(fmt.Sprintf(models.ErrResolvingVariables, err))
+			return
+		}
+		headers, err := headerEdit.resolvedText(resolve)
+		if err != nil {
+			finish(fmt.Sprintf(models.ErrResolvingVariables, err))
+			return
+		}
+		pathVariables := urlEdit.pathVariables()
+		for name, value := range pathVariables {
+			resolved, err := resolve(value)
+			if err != nil {
+				finish(fmt.Sprintf(models.ErrResolvingVariables, err))
+				return
+			}
+			pathVariables[name] = resolved
+		}
+		rawURL := httpclient.EncodeURL(resolvedURL, pathVariables)
+		encode, err := bodyEdit.encoder(resolve)
 		if err != nil {
-			progressBar.Hide()
-			progressBar.Refresh()
-			textRS.SetText(fmt.Sprintf("Error creating request: %v", err))
+			finish(fmt.Sprintf(models.ErrResolvingVariables, err))
 			return
 		}
+		auth, err := authenticator(authEdit.effectiveAuth(), environment(), resolve)
+		if err != nil {
+			finish(fmt.Sprintf(models.ErrCreatingRequest, err))
+			return
+		}
+		settings, err := settingsEdit.settings()
+		if err != nil {
+			finish(fmt.Sprintf(models.ErrInvalidSettings, err))
+			return
+		}
+		method := methodSelect.Selected
 
-		go func() {
-			statusLine, prettyBody, _, err := httpclient.SendRequest(rq)
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(httpclient.WithSettings(context.Background(), settings))
+		submitBtn.SetText(models.LabelCancel)
+		start := time.Now()
+		// done shows text and the response, if any
+		done := func(text string, resp *httpclient.Response) {
 			fyne.Do(func() {
-				progressBar.Hide()
-				progressBar.Refresh()
-				if err != nil {
-					textRS.SetText(fmt.Sprintf("%s", err))
-					return
+				cancel()
+				cancel = nil
+				submitBtn.SetText(models.LabelSend)
+				finish(text)
+				if resp != nil {
+					response.update(resp, text)
 				}
-				textRS.SetText(statusLine + prettyBody)
 			})
+		}
+
+		// Tick the elapsed time until the request is done
+		elapsedLabel.SetText(fmt.Sprintf(models.ErrRequestInProgress, "0s"))
+		elapsedLabel.Show()
+		go func() {
+			ticker := time.NewTicker(100 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					elapsed := time.Since(start).Round(100 * time.Millisecond)
+					fyne.Do(func() {
+						if ctx.Err() == nil {
+							elapsedLabel.SetText(fmt.Sprintf(models.ErrRequestInProgress, elapsed))
+						}
+					})
+				}
+			}
+		}()
+
+		// Build and send request in goroutine, the body may read files and
+		// auth may fetch a token first
+		go func() {
+			body, err := encode()
+			var rq *http.Request
+			if err == nil {
+				rq, err = httpclient.NewRequestWithBody(ctx, method, rawURL, body, headers, auth)
+			}
+			if errors.Is(err, context.Canceled) {
+				done(cancelledText(time.Since(start)), nil)
+				return
+			}
+			if err != nil {
+				done(fmt.Sprintf(models.ErrCreatingRequest, err), nil)
+				return
+			}
+			resp, err := httpclient.Send(rq)
+			switch {
+			case errors.Is(err, context.Canceled):
+				done(cancelledText(time.Since(start)), nil)
+			case err != nil:
+				done(err.Error(), nil)
+			case httpclient.Challenged(rq):
+				done(models.MsgAuthChallenge, resp)
+			default:
+				done("", resp)
+			}
 		}()
 	})
 
-	frm.Append("", submitBtn)
+	// Add save button with status
+	saveStatus := widget.NewLabel("")
+	saveForm := func() {
+		edited := applyEdits(*request, urlEdit.url, methodSelect.Selected, headerEdit.headers(), bodyEdit.body(), authEdit.auth())
+		if err := save(&edited); err != nil {
+			saveStatus.SetText(fmt.Sprintf(models.ErrSavingRequest, err))
+			return
+		}
+		*request = edited
+		headerEdit.saved(edited.Header)
+		saveStatus.SetText(models.MsgRequestSaved)
+	}
+	saveBtn := widget.NewButton(models.LabelSave, saveForm)
+
+	frm.Append("", container.NewBorder(nil, nil, nil, container.NewHBox(saveStatus, saveBtn), submitBtn))
 	frm.Append("", progressBar)
 
+	// Create response container
 	containerRS := container.NewVBox(
 		progressBar,
-		textRS,
+		elapsedLabel,
+		response.content,
 	)
-	frm.Append("Response", containerRS)
+	frm.Append(models.LabelResponse, containerRS)
+
+	return &RequestForm{
+		Content: container.NewVBox(frm),
+		Save:    saveForm,
+		Refresh: func() { refreshPreview("") },
+	}
+}
+
+// cancelledText is the response text of a request cancelled after elapsed
+func cancelledText(elapsed time.Duration) string {
+	return models.ErrRequestCancelled + "\n" + fmt.Sprintf(models.MsgElapsed, elapsed.Round(time.Millisecond))
+}
 
-	return container.NewVBox(frm)
+// applyEdits returns a copy of request with the values of the form editors
+func applyEdits(request collection.Request, url collection.URL, method string, header collection.HeaderList, body *collection.Body, auth *collection.Auth) collection.Request {
+	request.URL = url
+	request.Method = method
+	request.Header = header
+	request.Auth = auth
+	request.Body = body
+	return request
 }
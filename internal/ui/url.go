@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"slices"
+
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/romanitalian/GHOSTman/v2/internal/collection"
+)
+
+// urlEditor edits the request URL in the URL entry and in tables of its
+// query parameters and path variables, kept in sync with the entry
+type urlEditor struct {
+	url collection.URL
+
+	entry     *widget.Entry
+	query     *keyValueTable
+	variables *keyValueTable
+	// syncing is set while the editors are updated from each other
+	syncing bool
+
+	// OnChanged is called after every edit of the URL
+	OnChanged func()
+}
+
+// newURLEditor returns an editor of url
+func newURLEditor(url collection.URL) *urlEditor {
+	e := &urlEditor{
+		url:       url,
+		entry:     widget.NewEntry(),
+		query:     newKeyValueTable(false),
+		variables: newKeyValueTable(false),
+	}
+	e.variables.FixKeys()
+	// values of the path variables are edited in place
+	e.url.Variable = slices.Clone(url.Variable)
+	if len(e.url.Variable) == 0 {
+		// collections may lack the path variables of the raw URL
+		derived := e.url
+		derived.SetRaw(e.url.Raw)
+		e.url.Variable = derived.Variable
+	}
+
+	e.syncing = true
+	e.entry.SetText(e.url.Raw)
+	e.setRows()
+	e.syncing = false
+
+	e.entry.OnChanged = func(raw string) {
+		if e.syncing {
+			return
+		}
+		e.url.SetRaw(raw)
+		e.syncing = true
+		e.setRows()
+		e.syncing = false
+		e.changed()
+	}
+	e.query.OnChanged = func() {
+		if e.syncing {
+			return
+		}
+		e.url.SetQuery(e.queryParams())
+		e.syncing = true
+		e.entry.SetText(e.url.Raw)
+		e.syncing = false
+		e.changed()
+	}
+	e.variables.OnChanged = func() {
+		for i, row := range e.variables.Rows() {
+			e.url.Variable[i].Value = row.Value
+		}
+		e.changed()
+	}
+	return e
+}
+
+func (e *urlEditor) changed() {
+	if e.OnChanged != nil {
+		e.OnChanged()
+	}
+}
+
+// setRows shows the query parameters and path variables of the URL
+func (e *urlEditor) setRows() {
+	query := make([]keyValueRow, len(e.url.Query))
+	for i, q := range e.url.Query {
+		query[i] = keyValueRow{Key: q.Key, Value: q.Value, Disabled: q.Disabled}
+	}
+	e.query.SetRows(query)
+
+	variables := make([]keyValueRow, len(e.url.Variable))
+	for i, v := range e.url.Variable {
+		variables[i] = keyValueRow{Key: v.Key, Value: v.Value}
+	}
+	e.variables.SetRows(variables)
+}
+
+// queryParams returns the query parameters of the table. Postman attributes
+// of the parameters are kept while their key stays the same.
+func (e *urlEditor) queryParams() []collection.QueryParam {
+	var params []collection.QueryParam
+	for i, row := range e.query.Rows() {
+		p := collection.QueryParam{Key: row.Key}
+		if i < len(e.url.Query) && e.url.Query[i].Key == row.Key {
+			p = e.url.Query[i]
+		}
+		p.Value = row.Value
+		p.Disabled = row.Disabled
+		params = append(params, p)
+	}
+	return params
+}
+
+// pathVariables returns the values of the path variables by name
+func (e *urlEditor) pathVariables() map[string]string {
+	values := make(map[string]string, len(e.url.Variable))
+	for _, v := range e.url.Variable {
+		values[v.Key] = v.Value
+	}
+	return values
+}
@@ -1,12 +1,8 @@
 package main
 
 import (
-	"bytes"
 	_ "embed"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"strings"
 
@@ -14,6 +10,7 @@ import (
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
@@ -21,17 +18,24 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	"github.com/romanitalian/GHOSTman/v2/internal/collection"
+	"github.com/romanitalian/GHOSTman/v2/internal/environment"
+	"github.com/romanitalian/GHOSTman/v2/internal/httpclient"
+	"github.com/romanitalian/GHOSTman/v2/internal/ui"
 	"github.com/romanitalian/GHOSTman/v2/models"
 )
 
 const (
 	preferenceCurrentForm    = "currentForm"
 	preferenceCollectionPath = "collectionPath"
-	minURLPathLength         = 2
-	defaultSplitOffset       = 0.2
-	responseHeightRatio      = 0.3
-	defaultWindowWidth       = 1024
-	defaultWindowHeight      = 768
+
+	preferenceEnvironmentPaths  = "environmentPaths"
+	preferenceActiveEnvironment = "activeEnvironment"
+
+	defaultSplitOffset  = 0.2
+	responseHeightRatio = 0.3
+	defaultWindowWidth  = 1024
+	defaultWindowHeight = 768
 
 	logLevel      = zerolog.WarnLevel
 	logFormatJSON = true
@@ -43,215 +47,176 @@ const (
 //go:embed FyneApp.toml
 var _ []byte
 
-var (
-	topWindow   fyne.Window
-	httpMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS", "CONNECT", "TRACE"}
-)
+var topWindow fyne.Window
 
-func substituteVariables(s string, vars map[string]string) string {
-	for k, v := range vars {
-		placeholder := "{{" + k + "}}"
-		s = strings.ReplaceAll(s, placeholder, v)
-	}
-	return s
-}
+// loadPostmanCollection builds the forms of the collection at filePath.
+// environmentVars returns the variables of the active environment, which
+// take precedence over the collection variables, and environmentName its name.
+func loadPostmanCollection(filePath string, environmentVars ui.VariablesFunc, environmentName ui.EnvironmentFunc) ([]models.Form, error) {
+	var forms []models.Form
 
-func createForm(item models.Item, vars map[string]string) fyne.CanvasObject {
-	// Create form fields
-	frm := &widget.Form{}
+	coll, err := collection.LoadPostmanCollection(filePath)
+	if err != nil {
+		log.Error().Err(err).Msg(models.LogLoadingForms)
+		return nil, err
+	}
 
-	// Add request info fields
-	urlEntry := widget.NewEntry()
-	urlEntry.SetText(substituteVariables(item.Request.URL.Raw, vars))
-	frm.Append(models.LabelURL, urlEntry)
+	// Store variables in map
+	vars := collection.VariableMap(coll.Variable)
+	log.Info().Fields(vars).Msg(models.LogLoadedVariables)
 
-	methodSelect := widget.NewSelect(httpMethods, func(value string) {})
-	methodSelect.SetSelected(item.Request.Method)
-	frm.Append(models.LabelMethod, methodSelect)
+	log.Info().Int("count", len(coll.Item)).Msg(models.LogTotalItems)
 
-	var headersText strings.Builder
-	for _, h := range item.Request.Header {
-		headersText.WriteString(fmt.Sprintf("%s: %s\n", h.Key, substituteVariables(h.Value, vars)))
-	}
-	hdrsEntry := widget.NewMultiLineEntry()
-	hdrsEntry.SetText(headersText.String())
-	frm.Append(models.LabelHeaders, hdrsEntry)
-
-	// Create body field with fixed height
-	bodyEntry := widget.NewMultiLineEntry()
-	bodyEntry.SetText(substituteVariables(item.Request.Body.Raw, vars))
-
-	// Calculate number of lines in JSON
-	lines := strings.Count(item.Request.Body.Raw, "\n") + 1
-	bodyEntry.SetMinRowsVisible(lines)
-
-	frm.Append(models.LabelBody, bodyEntry)
-
-	// Create response field
-	textRS := widget.NewMultiLineEntry()
-	textRS.Wrapping = fyne.TextWrapWord
-	textRS.TextStyle = fyne.TextStyle{
-		Bold:      true,
-		Monospace: true,
+	loader := &formLoader{
+		collectionPath:  filePath,
+		vars:            vars,
+		environmentVars: environmentVars,
+		environmentName: environmentName,
+		seen:            make(map[string]bool),
 	}
-	textRS.Resize(fyne.NewSize(200, 200))
-	textRS.SetMinRowsVisible(25)
-
-	// Create progress bar
-	progressBar := widget.NewProgressBarInfinite()
-	progressBar.Hide() // Hide initially
-
-	// Add submit button
-	submitBtn := widget.NewButton(models.LabelSend, func() {
-		// Clear response field and show progress
-		textRS.SetText("")
-		progressBar.Show()
-		progressBar.Refresh()
-
-		// Create request
-		rq, err := http.NewRequest(methodSelect.Selected, urlEntry.Text, bytes.NewBufferString(bodyEntry.Text))
-		if err != nil {
-			progressBar.Hide()
-			progressBar.Refresh()
-			textRS.SetText(fmt.Sprintf(models.ErrCreatingRequest, err))
-			return
-		}
+	forms = loader.appendItems(forms, coll.Item, "", nil, coll.Auth)
 
-		// Add headers
-		hdrs := strings.Split(hdrsEntry.Text, "\n")
-		for _, h := range hdrs {
-			if h == "" {
-				continue
-			}
-			parts := strings.SplitN(h, ":", 2)
-			if len(parts) == 2 {
-				rq.Header.Set(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
-			}
-		}
+	log.Info().Int("count", len(forms)).Msg(models.LogTotalForms)
+	for _, form := range forms {
+		log.Info().Str("form_id", form.ID).Str("title", form.Title).Msg(models.LogLoadedForm)
+	}
 
-		// Send request in goroutine
-		go func() {
-			client := &http.Client{}
-			resp, err := client.Do(rq)
-			if err != nil {
-				fyne.Do(func() {
-					progressBar.Hide()
-					progressBar.Refresh()
-					textRS.SetText(fmt.Sprintf(models.ErrSendingRequest, err))
-				})
-				return
-			}
-			defer resp.Body.Close()
-
-			// Read response
-			bodyRS, err := io.ReadAll(resp.Body)
-			if err != nil {
-				fyne.Do(func() {
-					progressBar.Hide()
-					progressBar.Refresh()
-					textRS.SetText(fmt.Sprintf(models.ErrReadingResponse, err))
-				})
-				return
-			}
+	return forms, nil
+}
 
-			// Format response
-			var prettyJSON bytes.Buffer
-			if err := json.Indent(&prettyJSON, bodyRS, "", "    "); err != nil {
-				fyne.Do(func() {
-					textRS.SetText(string(bodyRS))
-					progressBar.Hide()
-					progressBar.Refresh()
-				})
-				return
-			}
+// formLoader builds the forms of a loaded collection
+type formLoader struct {
+	collectionPath  string
+	vars            map[string]string
+	environmentVars ui.VariablesFunc
+	environmentName ui.EnvironmentFunc
+	// seen holds the IDs assigned so far and keeps them unique across the tree
+	seen map[string]bool
+}
 
-			// Update response and hide progress
-			fyne.Do(func() {
-				textRS.SetText(prettyJSON.String())
-				progressBar.Hide()
-				progressBar.Refresh()
-			})
-		}()
-	})
+// appendItems walks the items depth-first and appends a form for every folder
+// and request, so that each folder precedes its children in the result.
+// itemPath is the list of item indexes leading to items from the root and
+// auth is the auth they inherit.
+func (l *formLoader) appendItems(forms []models.Form, items []collection.Item, parentID string, itemPath []int, auth *collection.Auth) []models.Form {
+	for i, item := range items {
+		log.Info().Int("idx", i+1).Str("name", item.Name).Str("parent_id", parentID).Msg(models.LogProcessingItem)
+
+		formID := item.StableID(parentID, i)
+		for n := 2; l.seen[formID]; n++ {
+			log.Warn().Str("form_id", formID).Str("name", item.Name).Msg(models.LogDuplicateFormID)
+			formID = fmt.Sprintf("%s-%d", item.StableID(parentID, i), n)
+		}
+		l.seen[formID] = true
+		log.Info().Str("form_id", formID).Msg(models.LogFormID)
 
-	frm.Append("", submitBtn)
+		path := append(append([]int(nil), itemPath...), i)
 
-	frm.Append("", progressBar)
+		if item.IsFolder() {
+			forms = append(forms, models.Form{
+				ID:       formID,
+				ParentID: parentID,
+				Folder:   true,
+				Title:    item.Name,
+				Intro:    item.Description.String(),
+				Form:     container.NewVBox(),
+			})
+			log.Info().Str("folder_id", formID).Str("name", item.Name).Msg(models.LogAddedFolder)
 
-	// Create response container
-	containerRS := container.NewVBox(
-		progressBar,
-		textRS,
-	)
+			forms = l.appendItems(forms, item.Item, formID, path, collection.EffectiveAuth(item.Auth, auth))
+			continue
+		}
 
-	// Add response field after submit button
-	frm.Append(models.LabelResponse, containerRS)
+		// Create form with request info and variable substitution
+		form := ui.CreateForm(formID, item, auth, l.variables, l.environmentName, func(rq *collection.Request) error {
+			log.Info().Str("form_id", formID).Ints("item_path", path).Msg(models.LogSavingRequest)
+			return collection.SaveRequest(l.collectionPath, path, rq)
+		})
 
-	// Create vertical container with form
-	return container.NewVBox(
-		frm,
-	)
+		var intro string
+		if item.Request != nil {
+			intro = item.Request.Description.String()
+		}
+		forms = append(forms, models.Form{
+			ID:       formID,
+			ParentID: parentID,
+			Title:    item.Name,
+			Intro:    intro,
+			Form:     form.Content,
+			Save:     form.Save,
+			Refresh:  form.Refresh,
+		})
+		log.Info().Str("form_id", formID).Str("name", item.Name).Msg(models.LogAddedForm)
+	}
+	return forms
 }
 
-type Form struct {
-	ID    string
-	Title string
-	Intro string
-	Form  fyne.CanvasObject
+// variables returns the collection variables overridden by the active environment
+func (l *formLoader) variables() map[string]string {
+	return collection.MergeVariables(l.vars, l.environmentVars())
 }
 
-func loadPostmanCollection(filePath string) ([]models.Form, error) {
-	var forms []models.Form
-
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		log.Error().Err(err).Msg(models.LogLoadingForms)
-		return nil, fmt.Errorf(models.ErrReadingCollection, err)
+// loadEnvironments loads the Postman environments at paths, skipping and
+// logging the ones that fail to load
+func loadEnvironments(paths []string) ([]string, []*environment.Environment) {
+	var loadedPaths []string
+	var envs []*environment.Environment
+	for _, path := range paths {
+		env, err := environment.Load(path)
+		if err != nil {
+			log.Error().Err(err).Str("path", path).Msg(models.LogLoadingEnvironment)
+			continue
+		}
+		loadedPaths = append(loadedPaths, path)
+		envs = append(envs, env)
 	}
+	return loadedPaths, envs
+}
 
-	var collection models.Collection
-	if err := json.Unmarshal(data, &collection); err != nil {
-		log.Error().Err(err).Msg(models.LogLoadingForms)
-		return nil, fmt.Errorf(models.ErrParsingCollection, err)
+// findForm returns the form with the given ID
+func findForm(forms []models.Form, id string) (models.Form, bool) {
+	for _, f := range forms {
+		if f.ID == id {
+			return f, true
+		}
 	}
+	return models.Form{}, false
+}
 
-	// Store variables in map
-	vars := make(map[string]string)
-	for _, v := range collection.Variable {
-		vars[v.Key] = v.Value
+// firstRequest returns the ID of the first form that is not a folder
+func firstRequest(forms []models.Form) (string, bool) {
+	for _, f := range forms {
+		if !f.Folder {
+			return f.ID, true
+		}
 	}
-	log.Info().Fields(vars).Msg(models.LogLoadedVariables)
-
-	log.Info().Int("count", len(collection.Item)).Msg(models.LogTotalItems)
-
-	for i, item := range collection.Item {
-		log.Info().Int("idx", i+1).Str("name", item.Name).Msg(models.LogProcessingItem)
-		log.Info().Interface("url_path", item.Request.URL.Path).Msg(models.LogURLPath)
-		if len(item.Request.URL.Path) >= minURLPathLength {
-			formID := item.Request.URL.Path[1]
-			log.Info().Str("form_id", formID).Msg(models.LogFormID)
-
-			// Create form with request info and variable substitution
-			form := createForm(item, vars)
+	return "", false
+}
 
-			forms = append(forms, models.Form{
-				ID:    formID,
-				Title: item.Name,
-				Intro: item.Request.Description,
-				Form:  form,
-			})
-			log.Info().Str("form_id", formID).Str("name", item.Name).Msg(models.LogAddedForm)
-		} else {
-			log.Warn().Str("name", item.Name).Msg(models.LogSkippingItem)
+// filterForms keeps the forms whose title contains input, together with their
+// ancestors so they stay reachable in the tree. A matching folder keeps its
+// whole subtree.
+func filterForms(forms []models.Form, input string) []models.Form {
+	input = strings.ToLower(input)
+	matched := make(map[string]bool, len(forms))
+	keep := make(map[string]bool, len(forms))
+	for _, f := range forms {
+		if matched[f.ParentID] || strings.Contains(strings.ToLower(f.Title), input) {
+			matched[f.ID] = true
+			keep[f.ID] = true
+			for p, ok := findForm(forms, f.ParentID); ok; p, ok = findForm(forms, p.ParentID) {
+				keep[p.ID] = true
+			}
 		}
 	}
 
-	log.Info().Int("count", len(forms)).Msg(models.LogTotalForms)
-	for _, form := range forms {
-		log.Info().Str("form_id", form.ID).Str("title", form.Title).Msg(models.LogLoadedForm)
+	filtered := make([]models.Form, 0, len(keep))
+	for _, f := range forms {
+		if keep[f.ID] {
+			filtered = append(filtered, f)
+		}
 	}
-
-	return forms, nil
+	return filtered
 }
 
 func main() {
@@ -276,6 +241,10 @@ func main() {
 	w := a.NewWindow(appTitle)
 	topWindow = w
 
+	// Response bodies larger than the limit are truncated, or kept whole in
+	// temporary files removed on exit
+	defer httpclient.RemoveResponseFiles()
+
 	var forms []models.Form
 	var filteredForms []models.Form
 	var tree *widget.Tree
@@ -286,6 +255,9 @@ func main() {
 	intro := widget.NewLabel("Form description goes here")
 	intro.Wrapping = fyne.TextWrapWord
 
+	// currentForm is the form shown on the right, the target of File > Save
+	var currentForm models.Form
+
 	setForm := func(form fyne.CanvasObject, formTitle string, formIntro string) {
 		log.Info().Str("form_title", formTitle).Msg(models.LogSettingForm)
 		title.SetText(formTitle)
@@ -294,11 +266,32 @@ func main() {
 		content.Refresh()
 	}
 
+	// Load environments from preferences, the active one is identified by its path
+	environmentPaths, environments := loadEnvironments(a.Preferences().StringList(preferenceEnvironmentPaths))
+	activeEnvironment := -1
+	for i, path := range environmentPaths {
+		if path == a.Preferences().String(preferenceActiveEnvironment) {
+			activeEnvironment = i
+		}
+	}
+	environmentVars := func() map[string]string {
+		if activeEnvironment < 0 {
+			return nil
+		}
+		return environments[activeEnvironment].VariableMap()
+	}
+	environmentName := func() string {
+		if activeEnvironment < 0 {
+			return ""
+		}
+		return environments[activeEnvironment].Name
+	}
+
 	// Load initial collection from preferences
 	collectionPath := a.Preferences().String(preferenceCollectionPath)
 	if collectionPath != "" {
 		var err error
-		forms, err = loadPostmanCollection(collectionPath)
+		forms, err = loadPostmanCollection(collectionPath, environmentVars, environmentName)
 		if err != nil {
 			log.Error().Err(err).Str("path", collectionPath).Msg("Failed to load collection from saved path")
 			forms = []models.Form{} // Start with empty if load fails
@@ -311,20 +304,20 @@ func main() {
 
 	tree = &widget.Tree{
 		ChildUIDs: func(uid string) []string {
-			if uid == "" {
-				keys := make([]string, len(filteredForms))
-				for i, f := range filteredForms {
-					keys[i] = f.ID
+			keys := make([]string, 0)
+			for _, f := range filteredForms {
+				if f.ParentID == uid {
+					keys = append(keys, f.ID)
 				}
-				log.Info().Strs("keys", keys).Msg(models.LogTreeChildUIDs)
-				return keys
 			}
-			return []string{}
+			log.Info().Str("uid", uid).Strs("keys", keys).Msg(models.LogTreeChildUIDs)
+			return keys
 		},
 		IsBranch: func(uid string) bool {
 			isRoot := uid == ""
-			log.Debug().Str("uid", uid).Bool("is_root", isRoot).Msg(models.LogTreeIsBranch)
-			return isRoot
+			f, _ := findForm(filteredForms, uid)
+			log.Debug().Str("uid", uid).Bool("is_root", isRoot).Bool("is_folder", f.Folder).Msg(models.LogTreeIsBranch)
+			return isRoot || f.Folder
 		},
 		CreateNode: func(branch bool) fyne.CanvasObject {
 			log.Debug().Bool("branch", branch).Msg(models.LogTreeCreateNode)
@@ -336,35 +329,38 @@ func main() {
 				obj.(*widget.Label).SetText(models.LabelForms)
 				return
 			}
-			for _, f := range filteredForms {
-				if f.ID == uid {
-					log.Debug().Str("uid", uid).Str("title", f.Title).Msg(models.LogTreeUpdateNode)
-					obj.(*widget.Label).SetText(f.Title)
-					break
-				}
+			if f, ok := findForm(filteredForms, uid); ok {
+				log.Debug().Str("uid", uid).Str("title", f.Title).Msg(models.LogTreeUpdateNode)
+				obj.(*widget.Label).SetText(f.Title)
 			}
 		},
 		OnSelected: func(uid string) {
-			for _, f := range filteredForms {
-				if f.ID == uid {
-					log.Info().Str("uid", uid).Str("form", f.Title).Msg(models.LogTreeSelected)
+			if f, ok := findForm(filteredForms, uid); ok {
+				log.Info().Str("uid", uid).Str("form", f.Title).Msg(models.LogTreeSelected)
+				if !f.Folder {
 					a.Preferences().SetString(preferenceCurrentForm, uid)
-					setForm(f.Form, f.Title, f.Intro)
-					break
 				}
+				currentForm = f
+				setForm(f.Form, f.Title, f.Intro)
 			}
 		},
 	}
 
+	// selectForm opens all folders above the form and selects it
+	selectForm := func(id string) {
+		for f, ok := findForm(forms, id); ok && f.ParentID != ""; f, ok = findForm(forms, f.ParentID) {
+			tree.OpenBranch(f.ParentID)
+		}
+		tree.Select(id)
+	}
+
 	filterEntry = widget.NewEntry()
 	filterEntry.SetPlaceHolder(models.FilterPlaceholder)
 	filterEntry.Resize(fyne.NewSize(200, 40)) // Set minimum size for filter
 	filterEntry.OnChanged = func(input string) {
-		filteredForms = make([]models.Form, 0)
-		for _, f := range forms {
-			if strings.Contains(strings.ToLower(f.Title), strings.ToLower(input)) {
-				filteredForms = append(filteredForms, f)
-			}
+		filteredForms = filterForms(forms, input)
+		if input != "" {
+			tree.OpenAllBranches()
 		}
 		tree.Refresh()
 	}
@@ -379,6 +375,76 @@ func main() {
 	})
 	themeSelect.SetSelected(models.ThemeLight)
 
+	// Environment switcher
+	environmentSelect := widget.NewSelect(nil, nil)
+	refreshEnvironments := func() {
+		options := []string{models.LabelNoEnvironment}
+		for _, env := range environments {
+			options = append(options, env.Name)
+		}
+		environmentSelect.SetOptions(options)
+		environmentSelect.SetSelectedIndex(activeEnvironment + 1)
+	}
+	environmentSelect.OnChanged = func(string) {
+		selected := environmentSelect.SelectedIndex() - 1
+		if selected == activeEnvironment {
+			return
+		}
+		activeEnvironment = selected
+		activePath := ""
+		if activeEnvironment >= 0 {
+			activePath = environmentPaths[activeEnvironment]
+		}
+		log.Info().Str("path", activePath).Msg(models.LogEnvironmentSelected)
+		a.Preferences().SetString(preferenceActiveEnvironment, activePath)
+		for _, f := range forms {
+			if f.Refresh != nil {
+				f.Refresh()
+			}
+		}
+	}
+	refreshEnvironments()
+
+	importEnvironmentBtn := widget.NewButton(models.LabelImportEnvironment, func() {
+		fileDialog := dialog.NewFileOpen(
+			func(reader fyne.URIReadCloser, err error) {
+				if err != nil || reader == nil {
+					return
+				}
+				defer reader.Close()
+
+				filePath := reader.URI().Path()
+				env, loadErr := environment.Load(filePath)
+				if loadErr != nil {
+					dialog.ShowError(loadErr, w)
+					return
+				}
+
+				// Re-importing a file replaces the loaded copy
+				idx := len(environmentPaths)
+				for i, path := range environmentPaths {
+					if path == filePath {
+						idx = i
+					}
+				}
+				if idx == len(environmentPaths) {
+					environmentPaths = append(environmentPaths, filePath)
+					environments = append(environments, env)
+				} else {
+					environments[idx] = env
+				}
+				a.Preferences().SetStringList(preferenceEnvironmentPaths, environmentPaths)
+
+				activeEnvironment = -1
+				refreshEnvironments()
+				environmentSelect.SetSelectedIndex(idx + 1)
+			},
+			w,
+		)
+		fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+		fileDialog.Show()
+	})
+
 	// Кнопка для добавления коллекции
 	addCollectionBtn := widget.NewButton("Добавить коллекцию", func() {
 		fileDialog := dialog.NewFileOpen(
@@ -389,7 +455,7 @@ func main() {
 				defer reader.Close()
 
 				filePath := reader.URI().Path()
-				newForms, loadErr := loadPostmanCollection(filePath)
+				newForms, loadErr := loadPostmanCollection(filePath, environmentVars, environmentName)
 				if loadErr != nil {
 					dialog.ShowError(fmt.Errorf("не удалось загрузить коллекцию: %w", loadErr), w)
 					return
@@ -400,8 +466,8 @@ func main() {
 				filteredForms = make([]models.Form, len(forms))
 				copy(filteredForms, forms)
 				tree.Refresh()
-				if len(forms) > 0 {
-					tree.Select(forms[0].ID)
+				if id, ok := firstRequest(forms); ok {
+					selectForm(id)
 				}
 
 				a.Preferences().SetString(preferenceCollectionPath, filePath)
@@ -413,32 +479,23 @@ func main() {
 	})
 
 	top := container.NewVBox(
-		themeSelect,
+		container.NewGridWithColumns(2,
+			themeSelect,
+			container.NewBorder(nil, nil, nil, importEnvironmentBtn, environmentSelect),
+		),
 		addCollectionBtn,
 		title,
 		widget.NewSeparator(),
 		intro,
 	)
 
-	if len(forms) > 0 {
+	if id, ok := firstRequest(forms); ok {
 		currentFormID := a.Preferences().String(preferenceCurrentForm)
-		if currentFormID != "" {
-			// check if form exists
-			found := false
-			for _, f := range forms {
-				if f.ID == currentFormID {
-					found = true
-					break
-				}
-			}
-			if found {
-				tree.Select(currentFormID)
-			} else {
-				tree.Select(forms[0].ID)
-			}
-		} else {
-			tree.Select(forms[0].ID)
+		// check if form exists
+		if f, found := findForm(forms, currentFormID); found && !f.Folder {
+			id = currentFormID
 		}
+		selectForm(id)
 	}
 
 	// Create scrollable container for tree
@@ -452,6 +509,16 @@ func main() {
 	)
 	leftMenu.Resize(fyne.NewSize(200, defaultWindowHeight)) // Set minimum size for left menu
 
+	// Save menu item, also triggered by Ctrl+S (Cmd+S on macOS) while an entry has focus
+	saveItem := fyne.NewMenuItem(models.LabelSave, func() {
+		if currentForm.Save != nil {
+			currentForm.Save()
+		}
+	})
+	saveItem.Shortcut = &desktop.CustomShortcut{KeyName: fyne.KeyS, Modifier: fyne.KeyModifierShortcutDefault}
+	settingsItem := fyne.NewMenuItem(models.LabelSettingsMenu, func() { ui.ShowSettings(w) })
+	w.SetMainMenu(fyne.NewMainMenu(fyne.NewMenu(models.LabelFile, saveItem, settingsItem)))
+
 	split := container.NewHSplit(leftMenu, container.NewBorder(top, nil, nil, nil, content))
 	split.Offset = 0.2 // Adjust split offset for better proportions
 	w.SetContent(split)
@@ -8,8 +8,106 @@ const (
 	LabelBody     = "Body"
 	LabelResponse = "Response"
 	LabelSend     = "Send"
+	LabelCancel   = "Cancel"
+	LabelSave     = "Save"
+	LabelFile     = "File"
+	LabelPreview  = "Preview"
 	LabelForms    = "Forms"
 	LabelForm     = "Form"
+
+	LabelResolvedRequest   = "Resolved headers and body"
+	LabelNoEnvironment     = "No environment"
+	LabelImportEnvironment = "Import environment"
+
+	LabelAuth        = "Auth"
+	LabelAuthInherit = "Inherit auth from parent"
+	LabelAuthNone    = "No auth"
+	LabelAuthBasic   = "Basic Auth"
+	LabelAuthBearer  = "Bearer Token"
+	LabelAuthAPIKey  = "API Key"
+	LabelUsername    = "Username"
+	LabelPassword    = "Password"
+	LabelToken       = "Token"
+	LabelKey         = "Key"
+	LabelValue       = "Value"
+	LabelAddTo       = "Add to"
+
+	LabelAuthOAuth2           = "OAuth 2.0"
+	LabelGrantType            = "Grant type"
+	LabelTokenURL             = "Token URL"
+	LabelClientID             = "Client ID"
+	LabelClientSecret         = "Client secret"
+	LabelClientAuthentication = "Client authentication"
+	LabelScope                = "Scope"
+	LabelRefreshToken         = "Refresh token"
+	LabelHeaderPrefix         = "Header prefix"
+
+	LabelAuthAWSV4    = "AWS Signature"
+	LabelAuthDigest   = "Digest Auth"
+	LabelAccessKey    = "Access key"
+	LabelSecretKey    = "Secret key"
+	LabelSessionToken = "Session token"
+	LabelRegion       = "Region"
+	LabelService      = "Service"
+
+	LabelBodyNone       = "none"
+	LabelBodyRaw        = "raw"
+	LabelBodyURLEncoded = "x-www-form-urlencoded"
+	LabelBodyFormData   = "form-data"
+	LabelBodyFile       = "binary"
+	LabelBodyGraphQL    = "GraphQL"
+	LabelText           = "Text"
+	LabelQuery          = "Query"
+	LabelVariables      = "Variables"
+
+	LabelQueryParams   = "Query params"
+	LabelPathVariables = "Path variables"
+
+	LabelTiming           = "Timing"
+	LabelTimingDNS        = "DNS lookup"
+	LabelTimingConnect    = "TCP connect"
+	LabelTimingTLS        = "TLS handshake"
+	LabelTimingWait       = "Waiting (TTFB)"
+	LabelTimingDownload   = "Download"
+	LabelTimingTotal      = "Total"
+	LabelCookies          = "Cookies"
+	LabelRaw              = "Raw"
+	LabelViewPretty       = "Pretty"
+	LabelViewSource       = "Source"
+	LabelViewPreview      = "Preview"
+	LabelViewImage        = "Image"
+	LabelViewHex          = "Hex"
+	LabelViewTree         = "Tree"
+	LabelCopyPath         = "Copy JSONPath"
+	LabelCopyValue        = "Copy value"
+	LabelSaveBody         = "Save…"
+	LabelOpenBody         = "Open"
+	LabelCopyBody         = "Copy body"
+	LabelCopyHeaders      = "Copy headers"
+	LabelCopyHAR          = "Copy as HAR"
+	LabelSettings         = "Settings"
+	LabelSettingsMenu     = "Settings…"
+	LabelRequestSettings  = "Request settings"
+	LabelTimeout          = "Timeout (s)"
+	LabelRedirects        = "Redirects"
+	LabelFollowRedirects  = "Follow redirects"
+	LabelMaxRedirects     = "Max redirects"
+	LabelProxy            = "Proxy"
+	LabelNoProxy          = "No proxy"
+	LabelVerifyTLS        = "Verify TLS certificates"
+	LabelTLS              = "TLS certificates"
+	LabelMaxResponseSize  = "Max response size (bytes)"
+	LabelKeepFullResponse = "Keep larger responses in files"
+	LabelDefault          = "Default"
+	LabelFollow           = "Follow"
+	LabelDontFollow       = "Don't follow"
+	LabelVerify           = "Verify"
+	LabelDontVerify       = "Don't verify"
+	LabelClientCerts      = "Client certificates"
+	LabelCertificates     = "Certificates"
+	LabelTLSVersion       = "Protocol"
+	LabelCipherSuite      = "Cipher suite"
+	LabelCertificate      = "Certificate %d"
 )
 
 // Theme labels
@@ -20,39 +118,78 @@ const (
 
 // Filter labels
 const (
-	FilterPlaceholder = "Filter by form name"
+	FilterPlaceholder       = "Filter by form name"
+	PlaceholderSearchJSON   = "Search keys and values"
+	PlaceholderFilterJSON   = "Filter: $.items[*].id or .items[] | .id"
+	PlaceholderProxy        = "From HTTP_PROXY and HTTPS_PROXY, e.g. http://proxy:3128"
+	PlaceholderNoProxy      = "e.g. localhost, .internal, 10.0.0.0/8"
+	PlaceholderTLSHost      = "Host: api.internal, *.internal:8443 or *"
+	PlaceholderCertFile     = "Certificate: PEM, .p12 or .pfx file"
+	PlaceholderKeyFile      = "Key: PEM file, unless in the certificate file"
+	PlaceholderCertPassword = "Password of a .p12 or .pfx file, asked again after a restart"
+	PlaceholderCAFile       = "CA certificates: PEM file"
 )
 
 // Error messages
 const (
-	ErrReadingCollection = "error reading Postman collection: %v"
-	ErrParsingCollection = "error parsing Postman collection: %v"
-	ErrCreatingRequest   = "Error creating request: %v"
-	ErrSendingRequest    = "Error sending request: %v"
-	ErrReadingResponse   = "Error reading response: %v"
-	ErrRequestCancelled  = "Запрос отменен"
-	ErrRequestInProgress = "Запрос выполняется %s 🚀"
+	ErrCreatingRequest    = "Error creating request: %v"
+	ErrSendingRequest     = "Error sending request: %v"
+	ErrReadingResponse    = "Error reading response: %v"
+	ErrSavingRequest      = "Error saving request: %v"
+	ErrResolvingVariables = "Error resolving variables: %v"
+	ErrRequestCancelled   = "Запрос отменен"
+	ErrRequestInProgress  = "Запрос выполняется %s 🚀"
+	ErrInvalidSettings    = "Invalid settings: %v"
+	ErrInvalidTimeout     = "enter the timeout in seconds, 0 for none"
+	ErrInvalidCount       = "enter a whole number greater than 0"
+	ErrHeaderLineBreak    = "the value of header %s has a line break"
+)
+
+// Status messages
+const (
+	MsgRequestSaved        = "Saved"
+	MsgUnresolvedVariables = "Unresolved variables: %s"
+	MsgInheritedAuth       = "Uses %s from the parent folder or collection"
+	MsgNoInheritedAuth     = "No auth is set on the parent folders or collection"
+	MsgAuthChallenge       = "🔐 Answered an authentication challenge of the server"
+	MsgNoBody              = "This request does not have a body"
+	MsgElapsed             = "⏱ %s"
+	MsgReusedConnection    = "(reused connection)"
+	MsgResponseSummary     = "%s %s   %s   %s"
+	MsgNoCookies           = "The response does not set cookies"
+	MsgImageInfo           = "%s image, %d × %d px"
+	MsgNoMatches           = "No matches"
+	MsgFilterResults       = "Results: %d"
+	MsgBodyTruncated       = "⚠ The body is truncated at %d bytes"
+	MsgBodyTruncatedOf     = "⚠ The body is truncated at %d of %d bytes"
+	MsgBodyChunk           = "Chunk %d/%d"
+	MsgNoTLS               = "The response was not received over TLS"
+	MsgCertificate         = "Subject: %s\nIssuer: %s\nValid: %s to %s\nNames: %s\nSerial: %s\nSHA-256: %s"
 )
 
 // Log messages
 const (
-	LogStartingApp        = "Starting application..."
-	LogWindowReady        = "Application window created and ready"
-	LogSettingForm        = "Setting form"
-	LogLoadingForms       = "Error loading forms"
-	LogLoadedVariables    = "Loaded Postman variables"
-	LogTotalItems         = "Total items in collection"
-	LogProcessingItem     = "Processing item"
-	LogURLPath            = "URL Path"
-	LogFormID             = "Form ID"
-	LogAddedForm          = "Added form"
-	LogSkippingItem       = "Skipping item: invalid URL path length"
-	LogTotalForms         = "Total forms loaded"
-	LogLoadedForm         = "Loaded form"
-	LogTreeChildUIDs      = "Tree ChildUIDs called for root"
-	LogTreeIsBranch       = "Tree IsBranch called"
-	LogTreeCreateNode     = "Tree CreateNode called"
-	LogTreeUpdateNode     = "Tree UpdateNode called"
-	LogTreeUpdateNodeRoot = "Tree UpdateNode called for root"
-	LogTreeSelected       = "Tree OnSelected called"
+	LogStartingApp         = "Starting application..."
+	LogWindowReady         = "Application window created and ready"
+	LogSettingForm         = "Setting form"
+	LogLoadingForms        = "Error loading forms"
+	LogLoadedVariables     = "Loaded Postman variables"
+	LogTotalItems          = "Total items in collection"
+	LogProcessingItem      = "Processing item"
+	LogFormID              = "Form ID"
+	LogAddedForm           = "Added form"
+	LogAddedFolder         = "Added folder"
+	LogSavingRequest       = "Saving request"
+	LogLoadingEnvironment  = "Error loading environment"
+	LogEnvironmentSelected = "Environment selected"
+	LogDuplicateFormID     = "Duplicate form ID, adding suffix"
+	LogTotalForms          = "Total forms loaded"
+	LogLoadedForm          = "Loaded form"
+	LogTreeChildUIDs       = "Tree ChildUIDs called"
+	LogTreeIsBranch        = "Tree IsBranch called"
+	LogTreeCreateNode      = "Tree CreateNode called"
+	LogTreeUpdateNode      = "Tree UpdateNode called"
+	LogTreeUpdateNodeRoot  = "Tree UpdateNode called for root"
+	LogTreeSelected        = "Tree OnSelected called"
+	LogInvalidPreference   = "Invalid preference, using the defaults"
 )
@@ -2,10 +2,17 @@ package models
 
 import "fyne.io/fyne/v2"
 
-// Form represents a form in the application
+// Form represents a form in the application.
+// Folders are forms too: they have no request and group nested forms by ParentID.
 type Form struct {
-	ID    string
-	Title string
-	Intro string
-	Form  fyne.CanvasObject
+	ID       string
+	ParentID string
+	Folder   bool
+	Title    string
+	Intro    string
+	Form     fyne.CanvasObject
+	// Save writes the edited request back to the collection file, nil for folders
+	Save func()
+	// Refresh updates the form after the variables changed, nil for folders
+	Refresh func()
 }